@@ -29,10 +29,42 @@ type AdvertisementSpec struct {
 	// Resources available in this cluster
 	Resources ResourceMetrics `json:"resources"`
 
+	// NodePools breaks Resources down by node pool (optional)
+	// +optional
+	NodePools []NodePoolResources `json:"nodePools,omitempty"`
+
+	// Fragmentation describes how free resources are spread across nodes (optional)
+	// +optional
+	Fragmentation *FragmentationInfo `json:"fragmentation,omitempty"`
+
+	// StorageClasses breaks persistent storage down by StorageClass (optional)
+	// +optional
+	StorageClasses []StorageClassResources `json:"storageClasses,omitempty"`
+
+	// Capabilities describes the topology, platforms and software of the cluster (optional)
+	// +optional
+	Capabilities *ClusterCapabilities `json:"capabilities,omitempty"`
+
+	// GPUs breaks GPUs down by product and MIG profile (optional)
+	// +optional
+	GPUs []GPUModelResources `json:"gpus,omitempty"`
+
 	// Cost information (optional)
 	// +optional
 	Cost *CostInfo `json:"cost,omitempty"`
 
+	// Energy describes the carbon intensity and cost of the cluster's energy (optional)
+	// +optional
+	Energy *EnergyInfo `json:"energy,omitempty"`
+
+	// Forecast predicts the availability over the next hours from the collected history (optional)
+	// +optional
+	Forecast []AvailabilityForecast `json:"forecast,omitempty"`
+
+	// Maintenance withdraws the cluster from new reservations, e.g. during an upgrade (optional)
+	// +optional
+	Maintenance *MaintenanceMode `json:"maintenance,omitempty"`
+
 	// Timestamp when this advertisement was created
 	Timestamp metav1.Time `json:"timestamp"`
 }
@@ -54,6 +86,197 @@ type ResourceMetrics struct {
 
 	// Available - Allocatable minus Allocated (what's still schedulable)
 	Available ResourceQuantities `json:"available"`
+
+	// Borrowed - Capacity of Liqo virtual nodes, i.e. resources of peered remote clusters.
+	// It is never part of the figures above.
+	// +optional
+	Borrowed *ResourceQuantities `json:"borrowed,omitempty"`
+
+	// Preemptible - Share of Allocated held by pods below the preemption priority threshold,
+	// which could be reclaimed for higher-priority remote workloads
+	// +optional
+	Preemptible *ResourceQuantities `json:"preemptible,omitempty"`
+
+	// Pending - Requests of pods waiting to be scheduled, i.e. demand not yet allocated
+	// +optional
+	Pending *ResourceQuantities `json:"pending,omitempty"`
+
+	// Devices - DRA devices per DeviceClass, when Dynamic Resource Allocation is enabled
+	// +optional
+	Devices []DeviceClassResources `json:"devices,omitempty"`
+}
+
+// DeviceClassResources counts the Dynamic Resource Allocation devices selected by a DeviceClass
+type DeviceClassResources struct {
+	// DeviceClass name
+	DeviceClass string `json:"deviceClass"`
+
+	// Driver the class is restricted to (empty when it selects every driver)
+	// +optional
+	Driver string `json:"driver,omitempty"`
+
+	// Capacity - Devices published in ResourceSlices for the advertised nodes
+	Capacity int32 `json:"capacity"`
+
+	// Allocated - Devices allocated to ResourceClaims
+	Allocated int32 `json:"allocated"`
+
+	// Available - Capacity minus Allocated
+	Available int32 `json:"available"`
+}
+
+// NodePoolResources represents the resources of the nodes sharing a node pool label value
+type NodePoolResources struct {
+	// Name is the value of the node pool label ("default" for unlabeled nodes)
+	Name string `json:"name"`
+
+	// NodeCount is the number of advertised nodes in the pool
+	NodeCount int32 `json:"nodeCount"`
+
+	// Capacity - Total physical resources of the pool
+	Capacity ResourceQuantities `json:"capacity"`
+
+	// Allocatable - Capacity minus system reservations
+	Allocatable ResourceQuantities `json:"allocatable"`
+
+	// Allocated - Sum of resources requested by pods bound to the pool
+	Allocated ResourceQuantities `json:"allocated"`
+
+	// Available - Allocatable minus Allocated within the pool
+	Available ResourceQuantities `json:"available"`
+}
+
+// FragmentationInfo describes how free resources are spread across nodes
+type FragmentationInfo struct {
+	// LargestSchedulable - For each resource, the largest amount free on a single node.
+	// Values may come from different nodes.
+	LargestSchedulable ResourceQuantities `json:"largestSchedulable"`
+
+	// CPUChunks - Histogram of free CPU per node
+	// +optional
+	CPUChunks []FreeChunkBucket `json:"cpuChunks,omitempty"`
+
+	// MemoryChunks - Histogram of free memory per node
+	// +optional
+	MemoryChunks []FreeChunkBucket `json:"memoryChunks,omitempty"`
+}
+
+// FreeChunkBucket counts the nodes whose free amount of a resource falls in [LowerBound, UpperBound)
+type FreeChunkBucket struct {
+	// LowerBound of the bucket (inclusive)
+	LowerBound resource.Quantity `json:"lowerBound"`
+
+	// UpperBound of the bucket (exclusive), unset for the last bucket
+	// +optional
+	UpperBound *resource.Quantity `json:"upperBound,omitempty"`
+
+	// Nodes is the number of nodes in the bucket
+	Nodes int32 `json:"nodes"`
+}
+
+// ClusterCapabilities describes what kind of workloads the cluster can host
+type ClusterCapabilities struct {
+	// Regions of the advertised nodes (topology.kubernetes.io/region)
+	// +optional
+	Regions []string `json:"regions,omitempty"`
+
+	// Zones of the advertised nodes (topology.kubernetes.io/zone)
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+
+	// Architectures of the advertised nodes (e.g. amd64, arm64)
+	// +optional
+	Architectures []string `json:"architectures,omitempty"`
+
+	// OperatingSystems of the advertised nodes (e.g. linux, windows)
+	// +optional
+	OperatingSystems []string `json:"operatingSystems,omitempty"`
+
+	// KubernetesVersion of the API server
+	// +optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// CNIPlugin is the detected network plugin (e.g. cilium, calico)
+	// +optional
+	CNIPlugin string `json:"cniPlugin,omitempty"`
+
+	// CNIVersion is the image version of the network plugin
+	// +optional
+	CNIVersion string `json:"cniVersion,omitempty"`
+
+	// LiqoVersion is the image version of the Liqo controller manager
+	// +optional
+	LiqoVersion string `json:"liqoVersion,omitempty"`
+
+	// GPUProducts are the GPU models of the advertised nodes, as labeled by GPU feature discovery
+	// +optional
+	GPUProducts []string `json:"gpuProducts,omitempty"`
+}
+
+// GPUModelResources represents the GPUs of a single product on the advertised nodes
+type GPUModelResources struct {
+	// Product as labeled by GPU feature discovery (e.g. NVIDIA-A100-SXM4-40GB), "unknown" if unlabeled
+	Product string `json:"product"`
+
+	// Memory of each GPU (from nvidia.com/gpu.memory)
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+
+	// MIGStrategy of the nodes (none, single or mixed)
+	// +optional
+	MIGStrategy string `json:"migStrategy,omitempty"`
+
+	// NodeCount is the number of advertised nodes with this product
+	NodeCount int32 `json:"nodeCount"`
+
+	// Allocatable - Whole GPUs (or MIG devices under the single strategy) that pods can request
+	Allocatable resource.Quantity `json:"allocatable"`
+
+	// Allocated - GPUs requested by pods
+	Allocated resource.Quantity `json:"allocated"`
+
+	// Available - Allocatable minus Allocated
+	Available resource.Quantity `json:"available"`
+
+	// MIGProfiles lists the MIG devices exposed under the mixed strategy
+	// +optional
+	MIGProfiles []MIGProfileResources `json:"migProfiles,omitempty"`
+}
+
+// MIGProfileResources represents the MIG devices of a single profile
+type MIGProfileResources struct {
+	// Profile name (e.g. 1g.5gb), requested as nvidia.com/mig-<profile>
+	Profile string `json:"profile"`
+
+	// Allocatable MIG devices
+	Allocatable resource.Quantity `json:"allocatable"`
+
+	// Allocated MIG devices
+	Allocated resource.Quantity `json:"allocated"`
+
+	// Available - Allocatable minus Allocated
+	Available resource.Quantity `json:"available"`
+}
+
+// StorageClassResources represents the persistent storage offered through a StorageClass
+type StorageClassResources struct {
+	// Name of the StorageClass
+	Name string `json:"name"`
+
+	// Provisioner is the CSI driver backing the StorageClass
+	Provisioner string `json:"provisioner"`
+
+	// Capacity - Allocated plus Available, unset when the driver does not publish CSIStorageCapacity
+	// +optional
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+
+	// Allocated - Sum of the capacity of bound PersistentVolumeClaims
+	Allocated resource.Quantity `json:"allocated"`
+
+	// Available - Capacity reported by CSIStorageCapacity objects for new volumes,
+	// unset when the driver does not publish them
+	// +optional
+	Available *resource.Quantity `json:"available,omitempty"`
 }
 
 // ResourceQuantities represents resource amounts
@@ -68,9 +291,22 @@ type ResourceQuantities struct {
 	// +optional
 	GPU *resource.Quantity `json:"gpu,omitempty"`
 
-	// Storage (optional)
+	// Storage - Persistent storage of StorageClasses with known capacity plus node ephemeral storage (optional)
 	// +optional
 	Storage *resource.Quantity `json:"storage,omitempty"`
+
+	// EphemeralStorage - Node local ephemeral storage (optional)
+	// +optional
+	EphemeralStorage *resource.Quantity `json:"ephemeralStorage,omitempty"`
+
+	// Pods - Pod slots; allocated counts the pods holding a slot (optional)
+	// +optional
+	Pods *resource.Quantity `json:"pods,omitempty"`
+
+	// Extended resources keyed by resource name (e.g. amd.com/gpu, hugepages-2Mi), including
+	// the MIG devices of the GPUs (nvidia.com/mig-<profile>)
+	// +optional
+	Extended map[string]resource.Quantity `json:"extended,omitempty"`
 }
 
 // CostInfo represents cost information
@@ -81,8 +317,100 @@ type CostInfo struct {
 	// MemoryCost per GB per hour
 	MemoryCost string `json:"memoryCost,omitempty"`
 
+	// GPUCost per GPU per hour
+	// +optional
+	GPUCost string `json:"gpuCost,omitempty"`
+
+	// StorageCost per GB per hour
+	// +optional
+	StorageCost string `json:"storageCost,omitempty"`
+
 	// Currency for pricing
 	Currency string `json:"currency,omitempty"`
+
+	// NodePools lists the prices of each node pool; the cluster-wide costs above are
+	// their averages weighted by the allocatable amount of each pool. Dynamic pricing
+	// floors and ceilings bound each price separately.
+	// +optional
+	NodePools []NodePoolCost `json:"nodePools,omitempty"`
+}
+
+// MaintenanceMode stops the cluster from attracting reservations without stopping the agent.
+// Available is advertised as zero while the reservations already granted keep being honored.
+type MaintenanceMode struct {
+	// Enabled turns maintenance mode on
+	Enabled bool `json:"enabled"`
+
+	// Reason is reported to the broker (e.g. "Kubernetes upgrade")
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// AvailabilityForecast predicts the availability over a window starting at the advertisement timestamp
+type AvailabilityForecast struct {
+	// Horizon is the length of the window (e.g. 1h, 6h, 24h)
+	Horizon metav1.Duration `json:"horizon"`
+
+	// Available is the lowest availability predicted within the window; only CPU, Memory and GPU are forecast
+	Available ResourceQuantities `json:"available"`
+
+	// Method is EWMA for a moving average of recent history, or Seasonal when it is
+	// adjusted with the daily profile of at least a day of history
+	Method string `json:"method"`
+
+	// Samples is the number of history slots the forecast is based on
+	Samples int32 `json:"samples"`
+}
+
+// Forecast methods
+const (
+	// ForecastMethodEWMA is an exponentially weighted moving average of recent availability
+	ForecastMethodEWMA = "EWMA"
+	// ForecastMethodSeasonal adjusts the moving average with the availability at the same time on previous days
+	ForecastMethodSeasonal = "Seasonal"
+)
+
+// EnergyInfo represents the latest carbon intensity and energy cost reading
+type EnergyInfo struct {
+	// CarbonIntensity in gCO2eq per kWh
+	// +optional
+	CarbonIntensity string `json:"carbonIntensity,omitempty"`
+
+	// EnergyCost per kWh
+	// +optional
+	EnergyCost string `json:"energyCost,omitempty"`
+
+	// Currency of EnergyCost
+	// +optional
+	Currency string `json:"currency,omitempty"`
+
+	// Source of the reading: static, file or http
+	Source string `json:"source"`
+
+	// ObservedAt is when the reading was taken
+	ObservedAt metav1.Time `json:"observedAt"`
+}
+
+// NodePoolCost represents the prices of the nodes in a node pool
+type NodePoolCost struct {
+	// Name of the node pool
+	Name string `json:"name"`
+
+	// CPUCost per core per hour
+	// +optional
+	CPUCost string `json:"cpuCost,omitempty"`
+
+	// MemoryCost per GB per hour
+	// +optional
+	MemoryCost string `json:"memoryCost,omitempty"`
+
+	// GPUCost per GPU per hour
+	// +optional
+	GPUCost string `json:"gpuCost,omitempty"`
+
+	// StorageCost per GB per hour
+	// +optional
+	StorageCost string `json:"storageCost,omitempty"`
 }
 
 // AdvertisementStatus defines the observed state of Advertisement
@@ -95,15 +423,149 @@ type AdvertisementStatus struct {
 	// +optional
 	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
 
-	// Published indicates if this advertisement has been published to the broker
+	// Published indicates if the last attempt to publish this advertisement to the broker succeeded
 	// +optional
 	Published bool `json:"published,omitempty"`
 
+	// LastPublishTime is when this advertisement was last successfully published to the broker
+	// +optional
+	LastPublishTime *metav1.Time `json:"lastPublishTime,omitempty"`
+
+	// LastError is the error of the last failed publish, cleared once a publish succeeds
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// ConsecutiveFailures is the number of publish attempts that failed since the last success
+	// +optional
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+
 	// Message provides additional information about the status
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// ExcludedNodes is the number of ready nodes left out of the advertised capacity by the node selection policy
+	// +optional
+	ExcludedNodes int32 `json:"excludedNodes,omitempty"`
+
+	// AvailabilityConstraints tells, per resource, which limit determined the advertised availability
+	// +optional
+	// +listType=map
+	// +listMapKey=resource
+	AvailabilityConstraints []AvailabilityConstraint `json:"availabilityConstraints,omitempty"`
+
+	// Pricing records how the advertised prices were computed when dynamic pricing is enabled
+	// +optional
+	Pricing *PricingStatus `json:"pricing,omitempty"`
+
+	// Schedule reports the lending window in effect when the AdvertisementPolicy has a schedule
+	// +optional
+	Schedule *LendingScheduleStatus `json:"schedule,omitempty"`
+
+	// Conditions represent the latest available observations of the advertisement's state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// AvailabilityConstraint names the limit that determined the advertised availability of a resource
+type AvailabilityConstraint struct {
+	// Resource name (e.g. cpu, memory)
+	Resource string `json:"resource"`
+
+	// Constraint is the binding limit: FreeCapacity, AdvertisementPolicy, LendingSchedule,
+	// ResourceQuota or Maintenance
+	Constraint string `json:"constraint"`
 }
 
+// LendingScheduleStatus reports the state of the lending schedule
+type LendingScheduleStatus struct {
+	// Lending is false when nothing is advertised because no window is open
+	Lending bool `json:"lending"`
+
+	// Window is the name of the open window, empty outside every window
+	// +optional
+	Window string `json:"window,omitempty"`
+
+	// NextTransition is when the next window opens or the current one closes,
+	// unset when nothing changes within the next 7 days
+	// +optional
+	NextTransition *metav1.Time `json:"nextTransition,omitempty"`
+}
+
+// PricingStatus records the inputs and outcome of the last dynamic price computation
+type PricingStatus struct {
+	// ComputedAt is when the prices were computed
+	ComputedAt metav1.Time `json:"computedAt"`
+
+	// TimeOfDayMultiplier is the multiplier of the time window in effect ("1" outside any window)
+	TimeOfDayMultiplier string `json:"timeOfDayMultiplier"`
+
+	// Resources details the computation of each cluster-wide price
+	// +optional
+	// +listType=map
+	// +listMapKey=resource
+	Resources []ResourcePricing `json:"resources,omitempty"`
+}
+
+// ResourcePricing details how the advertised price of a resource was computed
+type ResourcePricing struct {
+	// Resource name: cpu, memory, gpu or storage
+	Resource string `json:"resource"`
+
+	// BasePrice is the configured price before adjustments
+	BasePrice string `json:"basePrice"`
+
+	// Utilization is the percentage of allocatable resources allocated to pods
+	Utilization int32 `json:"utilization"`
+
+	// UtilizationMultiplier is the multiplier read from the utilization curve
+	UtilizationMultiplier string `json:"utilizationMultiplier"`
+
+	// Price is the advertised price
+	Price string `json:"price"`
+
+	// Bound is Floor or Ceiling when the adjusted price was clamped
+	// +optional
+	Bound string `json:"bound,omitempty"`
+}
+
+// Price bounds
+const (
+	// PriceBoundFloor means the adjusted price was raised to the floor price
+	PriceBoundFloor = "Floor"
+	// PriceBoundCeiling means the adjusted price was lowered to the ceiling price
+	PriceBoundCeiling = "Ceiling"
+)
+
+// Availability constraints
+const (
+	// ConstraintFreeCapacity means everything not allocated or reserved is advertised
+	ConstraintFreeCapacity = "FreeCapacity"
+	// ConstraintAdvertisementPolicy means the AdvertisementPolicy headroom, overcommit or cap applied
+	ConstraintAdvertisementPolicy = "AdvertisementPolicy"
+	// ConstraintResourceQuota means the quota left in the offload namespaces is lower
+	ConstraintResourceQuota = "ResourceQuota"
+	// ConstraintMaintenance means the cluster is in maintenance mode and lends nothing
+	ConstraintMaintenance = "Maintenance"
+	// ConstraintLendingSchedule means the lending window in effect, or the lack of one, applied
+	ConstraintLendingSchedule = "LendingSchedule"
+)
+
+// Advertisement condition types
+const (
+	// ConditionUsageMetricsAvailable reports whether Resources.Used could be read from metrics.k8s.io
+	ConditionUsageMetricsAvailable = "UsageMetricsAvailable"
+	// ConditionMetricsCollected reports whether the cluster state could be collected
+	ConditionMetricsCollected = "MetricsCollected"
+	// ConditionPublished reports whether the advertisement was published to the broker
+	ConditionPublished = "Published"
+	// ConditionBrokerReachable reports whether the broker answered the last publish
+	ConditionBrokerReachable = "BrokerReachable"
+	// ConditionNodePoolPricing reports whether the node pool prices of the PricingPolicy apply
+	ConditionNodePoolPricing = "NodePoolPricing"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Namespaced
@@ -112,7 +574,11 @@ type AdvertisementStatus struct {
 // +kubebuilder:printcolumn:name="Available-CPU",type=string,JSONPath=`.spec.resources.available.cpu`
 // +kubebuilder:printcolumn:name="Allocatable-Mem",type=string,JSONPath=`.spec.resources.allocatable.memory`
 // +kubebuilder:printcolumn:name="Available-Mem",type=string,JSONPath=`.spec.resources.available.memory`
+// +kubebuilder:printcolumn:name="Largest-CPU",type=string,JSONPath=`.spec.fragmentation.largestSchedulable.cpu`
+// +kubebuilder:printcolumn:name="Largest-Mem",type=string,JSONPath=`.spec.fragmentation.largestSchedulable.memory`
+// +kubebuilder:printcolumn:name="Maintenance",type=boolean,JSONPath=`.spec.maintenance.enabled`,priority=1
 // +kubebuilder:printcolumn:name="Published",type=boolean,JSONPath=`.status.published`
+// +kubebuilder:printcolumn:name="Last-Publish",type=date,JSONPath=`.status.lastPublishTime`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Advertisement is the Schema for the advertisements API
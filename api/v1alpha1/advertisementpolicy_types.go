@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AdvertisementPolicySpec defines how much of the free capacity is advertised.
+// A policy applies to the Advertisement with the same name and namespace.
+type AdvertisementPolicySpec struct {
+	// Resources lists the per-resource lending rules; resources not listed are advertised as is
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Resources []ResourcePolicy `json:"resources,omitempty"`
+
+	// Schedule restricts lending to time windows (optional)
+	// +optional
+	Schedule *LendingSchedule `json:"schedule,omitempty"`
+}
+
+// LendingSchedule defines when capacity is lent. Within a window, its resource rules
+// apply on top of the policy Resources; outside every window, the OutsideWindows rules
+// apply, or nothing is advertised when there are none.
+type LendingSchedule struct {
+	// TimeZone of the window schedules as an IANA name (e.g. Europe/Rome), UTC if unset
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Windows during which capacity is lent; when windows overlap, the first listed applies
+	// +listType=map
+	// +listMapKey=name
+	Windows []LendingWindow `json:"windows"`
+
+	// OutsideWindows lists the resource rules applied outside every window (e.g. a cap of "10%").
+	// When empty, Available is zero outside the windows.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	OutsideWindows []ResourcePolicy `json:"outsideWindows,omitempty"`
+}
+
+// LendingWindow is a recurring period during which capacity is lent
+type LendingWindow struct {
+	// Name identifies the window in the Advertisement status
+	Name string `json:"name"`
+
+	// Schedule is a 5-field cron expression (minute hour day-of-month month day-of-week)
+	// giving the start of each occurrence, e.g. "0 19 * * 1-5" for weekday evenings
+	Schedule string `json:"schedule"`
+
+	// Duration of each occurrence, at most 7 days (e.g. 12h)
+	Duration metav1.Duration `json:"duration"`
+
+	// Resources lists rules applied within the window on top of the policy Resources,
+	// e.g. a headroom; when empty, the policy Resources alone apply
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Resources []ResourcePolicy `json:"resources,omitempty"`
+}
+
+// ResourcePolicy shapes the advertised availability of a single resource.
+// Headroom and Cap are either absolute quantities (e.g. "2", "4Gi") or
+// percentages of the cluster Allocatable (e.g. "10%").
+type ResourcePolicy struct {
+	// Name of the resource (e.g. cpu, memory, nvidia.com/gpu)
+	Name string `json:"name"`
+
+	// Headroom is kept free for local bursts and never advertised
+	// +optional
+	Headroom string `json:"headroom,omitempty"`
+
+	// OvercommitPercent scales Allocatable before allocations are subtracted,
+	// e.g. 150 lends up to 1.5 times the allocatable amount (defaults to 100)
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	OvercommitPercent *int32 `json:"overcommitPercent,omitempty"`
+
+	// Cap bounds the total lendable amount, including what is already reserved by provider instructions
+	// +optional
+	Cap string `json:"cap,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced
+
+// AdvertisementPolicy controls the buffer kept for local workloads.
+type AdvertisementPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AdvertisementPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AdvertisementPolicyList lists AdvertisementPolicy.
+type AdvertisementPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AdvertisementPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AdvertisementPolicy{}, &AdvertisementPolicyList{})
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PricingPolicySpec defines the hourly prices advertised with the cluster resources.
+// A policy applies to the Advertisement with the same name and namespace.
+type PricingPolicySpec struct {
+	// Currency of every price (e.g. EUR, USD)
+	Currency string `json:"currency"`
+
+	// Default prices apply to the nodes of pools without an entry in NodePools
+	Default ResourcePrices `json:"default"`
+
+	// NodePools overrides the default prices for specific node pools
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	NodePools []NodePoolPrices `json:"nodePools,omitempty"`
+
+	// Dynamic adjusts the prices above to the current utilization and time of day
+	// +optional
+	Dynamic *DynamicPricing `json:"dynamic,omitempty"`
+}
+
+// DynamicPricing scales the configured prices by a utilization multiplier and a
+// time-of-day multiplier, then bounds them by the floor and ceiling prices
+type DynamicPricing struct {
+	// UtilizationCurve maps the utilization (Allocated/Allocatable) of each resource
+	// to a multiplier, interpolating linearly between points. Below the first point
+	// and above the last one the multiplier of the nearest point applies.
+	// +optional
+	// +listType=map
+	// +listMapKey=utilization
+	UtilizationCurve []UtilizationPoint `json:"utilizationCurve,omitempty"`
+
+	// TimeOfDay multiplies prices within daily time windows; the first matching window applies
+	// +optional
+	TimeOfDay []TimeOfDayMultiplier `json:"timeOfDay,omitempty"`
+
+	// TimeZone of the time-of-day windows as an IANA name (e.g. Europe/Rome), UTC if unset
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Floor prices bound the adjusted prices from below
+	// +optional
+	Floor ResourcePrices `json:"floor,omitempty"`
+
+	// Ceiling prices bound the adjusted prices from above
+	// +optional
+	Ceiling ResourcePrices `json:"ceiling,omitempty"`
+}
+
+// UtilizationPoint is a point of the utilization curve
+type UtilizationPoint struct {
+	// Utilization in percent
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Utilization int32 `json:"utilization"`
+
+	// Multiplier applied to the price at this utilization (e.g. "1.5")
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	Multiplier string `json:"multiplier"`
+}
+
+// TimeOfDayMultiplier multiplies prices between Start and End every day.
+// A window whose End is not after its Start spans midnight.
+type TimeOfDayMultiplier struct {
+	// Start of the window (HH:MM, inclusive)
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// End of the window (HH:MM, exclusive)
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+
+	// Multiplier applied to the prices within the window (e.g. "0.8")
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	Multiplier string `json:"multiplier"`
+}
+
+// ResourcePrices holds hourly prices as decimal strings (e.g. "0.031").
+// Unset prices are not advertised.
+type ResourcePrices struct {
+	// CPU price per core per hour
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	CPU string `json:"cpu,omitempty"`
+
+	// Memory price per GB per hour
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	Memory string `json:"memory,omitempty"`
+
+	// GPU price per GPU per hour
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	GPU string `json:"gpu,omitempty"`
+
+	// Storage price per GB per hour
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	Storage string `json:"storage,omitempty"`
+}
+
+// NodePoolPrices overrides the prices of the nodes in a node pool
+type NodePoolPrices struct {
+	// Name is the value of the node pool label
+	Name string `json:"name"`
+
+	// Prices of the pool; unset prices fall back to the defaults
+	Prices ResourcePrices `json:"prices"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:printcolumn:name="Currency",type=string,JSONPath=`.spec.currency`
+// +kubebuilder:printcolumn:name="CPU",type=string,JSONPath=`.spec.default.cpu`
+// +kubebuilder:printcolumn:name="Memory",type=string,JSONPath=`.spec.default.memory`
+
+// PricingPolicy sets the prices advertised to the broker.
+type PricingPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PricingPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PricingPolicyList lists PricingPolicy.
+type PricingPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PricingPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PricingPolicy{}, &PricingPolicyList{})
+}
@@ -18,6 +18,10 @@ type ProviderInstructionSpec struct {
 	// RequestedMemory amount.
 	RequestedMemory string `json:"requestedMemory"`
 
+	// RequestedStorage amount.
+	// +optional
+	RequestedStorage string `json:"requestedStorage,omitempty"`
+
 	// Message is a human description.
 	// +optional
 	Message string `json:"message,omitempty"`
@@ -21,6 +21,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -83,13 +85,146 @@ func (in *AdvertisementList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdvertisementPolicy) DeepCopyInto(out *AdvertisementPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdvertisementPolicy.
+func (in *AdvertisementPolicy) DeepCopy() *AdvertisementPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AdvertisementPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AdvertisementPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdvertisementPolicyList) DeepCopyInto(out *AdvertisementPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AdvertisementPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdvertisementPolicyList.
+func (in *AdvertisementPolicyList) DeepCopy() *AdvertisementPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(AdvertisementPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AdvertisementPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdvertisementPolicySpec) DeepCopyInto(out *AdvertisementPolicySpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourcePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(LendingSchedule)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdvertisementPolicySpec.
+func (in *AdvertisementPolicySpec) DeepCopy() *AdvertisementPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AdvertisementPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AdvertisementSpec) DeepCopyInto(out *AdvertisementSpec) {
 	*out = *in
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePoolResources, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Fragmentation != nil {
+		in, out := &in.Fragmentation, &out.Fragmentation
+		*out = new(FragmentationInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
+		*out = make([]StorageClassResources, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(ClusterCapabilities)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GPUs != nil {
+		in, out := &in.GPUs, &out.GPUs
+		*out = make([]GPUModelResources, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Cost != nil {
 		in, out := &in.Cost, &out.Cost
 		*out = new(CostInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Energy != nil {
+		in, out := &in.Energy, &out.Energy
+		*out = new(EnergyInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Forecast != nil {
+		in, out := &in.Forecast, &out.Forecast
+		*out = make([]AvailabilityForecast, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(MaintenanceMode)
 		**out = **in
 	}
 	in.Timestamp.DeepCopyInto(&out.Timestamp)
@@ -109,6 +244,32 @@ func (in *AdvertisementSpec) DeepCopy() *AdvertisementSpec {
 func (in *AdvertisementStatus) DeepCopyInto(out *AdvertisementStatus) {
 	*out = *in
 	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	if in.LastPublishTime != nil {
+		in, out := &in.LastPublishTime, &out.LastPublishTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AvailabilityConstraints != nil {
+		in, out := &in.AvailabilityConstraints, &out.AvailabilityConstraints
+		*out = make([]AvailabilityConstraint, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pricing != nil {
+		in, out := &in.Pricing, &out.Pricing
+		*out = new(PricingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(LendingScheduleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdvertisementStatus.
@@ -121,9 +282,86 @@ func (in *AdvertisementStatus) DeepCopy() *AdvertisementStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AvailabilityConstraint) DeepCopyInto(out *AvailabilityConstraint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AvailabilityConstraint.
+func (in *AvailabilityConstraint) DeepCopy() *AvailabilityConstraint {
+	if in == nil {
+		return nil
+	}
+	out := new(AvailabilityConstraint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AvailabilityForecast) DeepCopyInto(out *AvailabilityForecast) {
+	*out = *in
+	out.Horizon = in.Horizon
+	in.Available.DeepCopyInto(&out.Available)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AvailabilityForecast.
+func (in *AvailabilityForecast) DeepCopy() *AvailabilityForecast {
+	if in == nil {
+		return nil
+	}
+	out := new(AvailabilityForecast)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCapabilities) DeepCopyInto(out *ClusterCapabilities) {
+	*out = *in
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OperatingSystems != nil {
+		in, out := &in.OperatingSystems, &out.OperatingSystems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GPUProducts != nil {
+		in, out := &in.GPUProducts, &out.GPUProducts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCapabilities.
+func (in *ClusterCapabilities) DeepCopy() *ClusterCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CostInfo) DeepCopyInto(out *CostInfo) {
 	*out = *in
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePoolCost, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostInfo.
@@ -136,6 +374,404 @@ func (in *CostInfo) DeepCopy() *CostInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClassResources) DeepCopyInto(out *DeviceClassResources) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClassResources.
+func (in *DeviceClassResources) DeepCopy() *DeviceClassResources {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClassResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynamicPricing) DeepCopyInto(out *DynamicPricing) {
+	*out = *in
+	if in.UtilizationCurve != nil {
+		in, out := &in.UtilizationCurve, &out.UtilizationCurve
+		*out = make([]UtilizationPoint, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeOfDay != nil {
+		in, out := &in.TimeOfDay, &out.TimeOfDay
+		*out = make([]TimeOfDayMultiplier, len(*in))
+		copy(*out, *in)
+	}
+	out.Floor = in.Floor
+	out.Ceiling = in.Ceiling
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicPricing.
+func (in *DynamicPricing) DeepCopy() *DynamicPricing {
+	if in == nil {
+		return nil
+	}
+	out := new(DynamicPricing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnergyInfo) DeepCopyInto(out *EnergyInfo) {
+	*out = *in
+	in.ObservedAt.DeepCopyInto(&out.ObservedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnergyInfo.
+func (in *EnergyInfo) DeepCopy() *EnergyInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(EnergyInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FragmentationInfo) DeepCopyInto(out *FragmentationInfo) {
+	*out = *in
+	in.LargestSchedulable.DeepCopyInto(&out.LargestSchedulable)
+	if in.CPUChunks != nil {
+		in, out := &in.CPUChunks, &out.CPUChunks
+		*out = make([]FreeChunkBucket, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MemoryChunks != nil {
+		in, out := &in.MemoryChunks, &out.MemoryChunks
+		*out = make([]FreeChunkBucket, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FragmentationInfo.
+func (in *FragmentationInfo) DeepCopy() *FragmentationInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(FragmentationInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FreeChunkBucket) DeepCopyInto(out *FreeChunkBucket) {
+	*out = *in
+	out.LowerBound = in.LowerBound.DeepCopy()
+	if in.UpperBound != nil {
+		in, out := &in.UpperBound, &out.UpperBound
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreeChunkBucket.
+func (in *FreeChunkBucket) DeepCopy() *FreeChunkBucket {
+	if in == nil {
+		return nil
+	}
+	out := new(FreeChunkBucket)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUModelResources) DeepCopyInto(out *GPUModelResources) {
+	*out = *in
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	out.Allocatable = in.Allocatable.DeepCopy()
+	out.Allocated = in.Allocated.DeepCopy()
+	out.Available = in.Available.DeepCopy()
+	if in.MIGProfiles != nil {
+		in, out := &in.MIGProfiles, &out.MIGProfiles
+		*out = make([]MIGProfileResources, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUModelResources.
+func (in *GPUModelResources) DeepCopy() *GPUModelResources {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUModelResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LendingSchedule) DeepCopyInto(out *LendingSchedule) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]LendingWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OutsideWindows != nil {
+		in, out := &in.OutsideWindows, &out.OutsideWindows
+		*out = make([]ResourcePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LendingSchedule.
+func (in *LendingSchedule) DeepCopy() *LendingSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(LendingSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LendingScheduleStatus) DeepCopyInto(out *LendingScheduleStatus) {
+	*out = *in
+	if in.NextTransition != nil {
+		in, out := &in.NextTransition, &out.NextTransition
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LendingScheduleStatus.
+func (in *LendingScheduleStatus) DeepCopy() *LendingScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LendingScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LendingWindow) DeepCopyInto(out *LendingWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourcePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LendingWindow.
+func (in *LendingWindow) DeepCopy() *LendingWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(LendingWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MIGProfileResources) DeepCopyInto(out *MIGProfileResources) {
+	*out = *in
+	out.Allocatable = in.Allocatable.DeepCopy()
+	out.Allocated = in.Allocated.DeepCopy()
+	out.Available = in.Available.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MIGProfileResources.
+func (in *MIGProfileResources) DeepCopy() *MIGProfileResources {
+	if in == nil {
+		return nil
+	}
+	out := new(MIGProfileResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceMode) DeepCopyInto(out *MaintenanceMode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceMode.
+func (in *MaintenanceMode) DeepCopy() *MaintenanceMode {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceMode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolCost) DeepCopyInto(out *NodePoolCost) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolCost.
+func (in *NodePoolCost) DeepCopy() *NodePoolCost {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolCost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolPrices) DeepCopyInto(out *NodePoolPrices) {
+	*out = *in
+	out.Prices = in.Prices
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolPrices.
+func (in *NodePoolPrices) DeepCopy() *NodePoolPrices {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolPrices)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolResources) DeepCopyInto(out *NodePoolResources) {
+	*out = *in
+	in.Capacity.DeepCopyInto(&out.Capacity)
+	in.Allocatable.DeepCopyInto(&out.Allocatable)
+	in.Allocated.DeepCopyInto(&out.Allocated)
+	in.Available.DeepCopyInto(&out.Available)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolResources.
+func (in *NodePoolResources) DeepCopy() *NodePoolResources {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PricingPolicy) DeepCopyInto(out *PricingPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PricingPolicy.
+func (in *PricingPolicy) DeepCopy() *PricingPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PricingPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PricingPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PricingPolicyList) DeepCopyInto(out *PricingPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PricingPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PricingPolicyList.
+func (in *PricingPolicyList) DeepCopy() *PricingPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(PricingPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PricingPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PricingPolicySpec) DeepCopyInto(out *PricingPolicySpec) {
+	*out = *in
+	out.Default = in.Default
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePoolPrices, len(*in))
+		copy(*out, *in)
+	}
+	if in.Dynamic != nil {
+		in, out := &in.Dynamic, &out.Dynamic
+		*out = new(DynamicPricing)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PricingPolicySpec.
+func (in *PricingPolicySpec) DeepCopy() *PricingPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PricingPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PricingStatus) DeepCopyInto(out *PricingStatus) {
+	*out = *in
+	in.ComputedAt.DeepCopyInto(&out.ComputedAt)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourcePricing, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PricingStatus.
+func (in *PricingStatus) DeepCopy() *PricingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PricingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderInstruction) DeepCopyInto(out *ProviderInstruction) {
 	*out = *in
@@ -336,6 +972,26 @@ func (in *ResourceMetrics) DeepCopyInto(out *ResourceMetrics) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.Available.DeepCopyInto(&out.Available)
+	if in.Borrowed != nil {
+		in, out := &in.Borrowed, &out.Borrowed
+		*out = new(ResourceQuantities)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Preemptible != nil {
+		in, out := &in.Preemptible, &out.Preemptible
+		*out = new(ResourceQuantities)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pending != nil {
+		in, out := &in.Pending, &out.Pending
+		*out = new(ResourceQuantities)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]DeviceClassResources, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceMetrics.
@@ -348,6 +1004,56 @@ func (in *ResourceMetrics) DeepCopy() *ResourceMetrics {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcePolicy) DeepCopyInto(out *ResourcePolicy) {
+	*out = *in
+	if in.OvercommitPercent != nil {
+		in, out := &in.OvercommitPercent, &out.OvercommitPercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePolicy.
+func (in *ResourcePolicy) DeepCopy() *ResourcePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcePrices) DeepCopyInto(out *ResourcePrices) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePrices.
+func (in *ResourcePrices) DeepCopy() *ResourcePrices {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcePrices)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcePricing) DeepCopyInto(out *ResourcePricing) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePricing.
+func (in *ResourcePricing) DeepCopy() *ResourcePricing {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcePricing)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceQuantities) DeepCopyInto(out *ResourceQuantities) {
 	*out = *in
@@ -363,6 +1069,23 @@ func (in *ResourceQuantities) DeepCopyInto(out *ResourceQuantities) {
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.EphemeralStorage != nil {
+		in, out := &in.EphemeralStorage, &out.EphemeralStorage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Extended != nil {
+		in, out := &in.Extended, &out.Extended
+		*out = make(map[string]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuantities.
@@ -374,3 +1097,59 @@ func (in *ResourceQuantities) DeepCopy() *ResourceQuantities {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageClassResources) DeepCopyInto(out *StorageClassResources) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	out.Allocated = in.Allocated.DeepCopy()
+	if in.Available != nil {
+		in, out := &in.Available, &out.Available
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageClassResources.
+func (in *StorageClassResources) DeepCopy() *StorageClassResources {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageClassResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeOfDayMultiplier) DeepCopyInto(out *TimeOfDayMultiplier) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeOfDayMultiplier.
+func (in *TimeOfDayMultiplier) DeepCopy() *TimeOfDayMultiplier {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeOfDayMultiplier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UtilizationPoint) DeepCopyInto(out *UtilizationPoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UtilizationPoint.
+func (in *UtilizationPoint) DeepCopy() *UtilizationPoint {
+	if in == nil {
+		return nil
+	}
+	out := new(UtilizationPoint)
+	in.DeepCopyInto(out)
+	return out
+}
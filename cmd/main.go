@@ -22,6 +22,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -33,9 +35,11 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -47,7 +51,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+	"github.com/mehdiazizian/liqo-resource-agent/internal/carbon"
 	"github.com/mehdiazizian/liqo-resource-agent/internal/controller"
+	"github.com/mehdiazizian/liqo-resource-agent/internal/forecast"
 	"github.com/mehdiazizian/liqo-resource-agent/internal/metrics"
 	// +kubebuilder:scaffold:imports
 )
@@ -84,6 +90,34 @@ func main() {
 	var instructionNamespace string
 	var brokerNamespace string
 	var advertisementRequeueInterval time.Duration
+	var extendedResources string
+	var enableUsageMetrics bool
+	var enableDRA bool
+	var aggregatorResyncInterval time.Duration
+	var nodePoolLabel string
+	var nodeSelector string
+	var excludeControlPlaneNodes bool
+	var excludeUnschedulableNodes bool
+	var excludeTaintedNodes bool
+	var nodeTolerations string
+	var countTerminatingPods bool
+	var countUnscheduledPods bool
+	var reportPendingDemand bool
+	var advertiseStorage bool
+	var preemptiblePriorityThreshold string
+	var offloadNamespaces string
+	var liqoNamespace string
+	var energyProvider string
+	var energySource string
+	var energyCarbonIntensity string
+	var energyCost string
+	var energyCurrency string
+	var energyRefreshInterval time.Duration
+	var enableForecast bool
+	var forecastHistory time.Duration
+	var forecastNamespace string
+	var withdrawOnShutdown bool
+	var withdrawOnDelete bool
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -112,6 +146,58 @@ func main() {
 	flag.StringVar(&instructionNamespace, "instruction-namespace", "", "Namespace for ReservationInstruction objects (defaults to advertisement namespace)")
 	flag.StringVar(&brokerNamespace, "broker-namespace", "default", "Namespace containing broker CRDs")
 	flag.DurationVar(&advertisementRequeueInterval, "advertisement-requeue-interval", 30*time.Second, "Interval for periodic advertisement updates")
+	flag.StringVar(&extendedResources, "extended-resources", "",
+		"Comma-separated extended resources to advertise besides nvidia.com/gpu and MIG devices; a trailing * matches a prefix "+
+			"(e.g. amd.com/gpu,gpu.intel.com/*,hugepages-*)")
+	flag.BoolVar(&enableUsageMetrics, "enable-usage-metrics", false,
+		"If set, populate the advertised Used resources from the metrics.k8s.io API (metrics-server)")
+	flag.BoolVar(&enableDRA, "enable-dra", false,
+		"If set, advertise Dynamic Resource Allocation devices per DeviceClass from the resource.k8s.io/v1 API")
+	flag.DurationVar(&aggregatorResyncInterval, "aggregator-resync-interval", 10*time.Minute,
+		"Interval for full recomputation of the incrementally aggregated node and pod totals")
+	flag.StringVar(&nodePoolLabel, "node-pool-label", "",
+		"Node label used to break advertised resources down by node pool (e.g. cloud.google.com/gke-nodepool); empty disables it")
+	flag.StringVar(&nodeSelector, "node-selector", "", "Label selector restricting the nodes whose capacity is advertised")
+	flag.BoolVar(&excludeControlPlaneNodes, "exclude-control-plane-nodes", false, "If set, control-plane nodes are not advertised")
+	flag.BoolVar(&excludeUnschedulableNodes, "exclude-unschedulable-nodes", false, "If set, cordoned nodes are not advertised")
+	flag.BoolVar(&excludeTaintedNodes, "exclude-tainted-nodes", false,
+		"If set, nodes with NoSchedule or NoExecute taints not matched by --node-tolerations are not advertised")
+	flag.StringVar(&nodeTolerations, "node-tolerations", "",
+		"Comma-separated taints that do not exclude a node, as key[=value][:effect] (e.g. dedicated=batch:NoSchedule)")
+	flag.BoolVar(&countTerminatingPods, "count-terminating-pods", false,
+		"If set, pods being deleted keep counting as allocated until they are removed")
+	flag.BoolVar(&countUnscheduledPods, "count-unscheduled-pods", false,
+		"If set, Pending pods not yet bound to a node count as allocated")
+	flag.BoolVar(&reportPendingDemand, "report-pending-demand", false,
+		"If set, advertise the requests of unscheduled pods as a separate Pending quantity")
+	flag.BoolVar(&advertiseStorage, "advertise-storage", false,
+		"If set, advertise persistent storage from CSIStorageCapacity and bound PVCs, plus node ephemeral storage")
+	flag.StringVar(&preemptiblePriorityThreshold, "preemptible-priority-threshold", "",
+		"Pod priority below which allocated resources are advertised as preemptible (e.g. 1000); empty disables it")
+	flag.StringVar(&offloadNamespaces, "offload-namespaces", "",
+		"Comma-separated namespaces hosting offloaded workloads; advertised availability is capped by their remaining ResourceQuota")
+	flag.StringVar(&liqoNamespace, "liqo-namespace", "liqo", "Namespace where Liqo is installed, used to detect its version")
+	flag.StringVar(&energyProvider, "energy-provider", "",
+		"Source of the advertised carbon intensity and energy cost (static|file|http, empty disables it)")
+	flag.StringVar(&energySource, "energy-source", "", "File path or URL of the JSON energy reading for the file and http providers")
+	flag.StringVar(&energyCarbonIntensity, "energy-carbon-intensity", "", "Carbon intensity in gCO2eq/kWh for the static provider")
+	flag.StringVar(&energyCost, "energy-cost", "", "Energy cost per kWh for the static provider")
+	flag.StringVar(&energyCurrency, "energy-currency", "", "Currency of the energy cost for the static provider")
+	flag.DurationVar(&energyRefreshInterval, "energy-refresh-interval", 15*time.Minute,
+		"Interval between energy readings, independent of advertisement updates")
+	flag.BoolVar(&enableForecast, "enable-forecast", false,
+		"If set, keep a history of the advertised availability and publish forecasts for the next 1h, 6h and 24h")
+	flag.DurationVar(&forecastHistory, "forecast-history", 7*24*time.Hour,
+		"Length of the availability history kept for forecasting (at least 24h)")
+	flag.StringVar(&forecastNamespace, "forecast-namespace", "",
+		"Namespace of the forecast history ConfigMap (defaults to the agent namespace from POD_NAMESPACE, "+
+			"where its Role grants access, then to the advertisement namespace)")
+	flag.BoolVar(&withdrawOnShutdown, "withdraw-on-shutdown", true,
+		"If set, withdraw the advertisement from the broker when the agent stops. "+
+			"Disable it to keep the cluster advertised across rolling upgrades")
+	flag.BoolVar(&withdrawOnDelete, "withdraw-on-delete", false,
+		"If set, a finalizer keeps the Advertisement until it has been withdrawn from the broker. "+
+			"The finalizer must be removed by hand if the agent is uninstalled before the Advertisement")
 
 	opts := zap.Options{
 		Development: true,
@@ -124,6 +210,12 @@ func main() {
 	if instructionNamespace == "" {
 		instructionNamespace = advertisementNamespace
 	}
+	if forecastNamespace == "" {
+		forecastNamespace = os.Getenv("POD_NAMESPACE")
+	}
+	if forecastNamespace == "" {
+		forecastNamespace = advertisementNamespace
+	}
 
 	ctx := context.Background()
 	cfg := ctrl.GetConfigOrDie()
@@ -312,15 +404,106 @@ func main() {
 		setupLog.Info("Broker transport not specified, broker communication disabled")
 	}
 
-	if err = (&controller.AdvertisementReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		MetricsCollector: &metrics.Collector{
-			ClusterIDOverride: clusterID,
+	selector, err := labels.Parse(nodeSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid node selector", "node-selector", nodeSelector)
+		os.Exit(1)
+	}
+	tolerations, err := metrics.ParseTolerations(nodeTolerations)
+	if err != nil {
+		setupLog.Error(err, "invalid node tolerations", "node-tolerations", nodeTolerations)
+		os.Exit(1)
+	}
+	var preemptionThreshold *int32
+	if preemptiblePriorityThreshold != "" {
+		threshold, err := strconv.ParseInt(preemptiblePriorityThreshold, 10, 32)
+		if err != nil {
+			setupLog.Error(err, "invalid preemptible priority threshold",
+				"preemptible-priority-threshold", preemptiblePriorityThreshold)
+			os.Exit(1)
+		}
+		value := int32(threshold)
+		preemptionThreshold = &value
+	}
+
+	metricsCollector := &metrics.Collector{
+		ClusterIDOverride: clusterID,
+		ExtendedResources: splitList(extendedResources),
+		NodePoolLabel:     nodePoolLabel,
+		NodeSelector: &metrics.NodeSelector{
+			LabelSelector:        selector,
+			ExcludeControlPlane:  excludeControlPlaneNodes,
+			ExcludeUnschedulable: excludeUnschedulableNodes,
+			ExcludeTainted:       excludeTaintedNodes,
+			Tolerations:          tolerations,
+		},
+		PodAccounting: metrics.PodAccountingPolicy{
+			IncludeTerminating: countTerminatingPods,
+			IncludeUnscheduled: countUnscheduledPods,
+			ReportPending:      reportPendingDemand,
 		},
+		AdvertiseStorage:             advertiseStorage,
+		PreemptiblePriorityThreshold: preemptionThreshold,
+		OffloadNamespaces:            splitList(offloadNamespaces),
+		LiqoNamespace:                liqoNamespace,
+	}
+	if enableUsageMetrics {
+		// metrics.k8s.io cannot be watched, so usage is always read directly from the API server
+		metricsCollector.UsageSource = &metrics.MetricsServerUsageSource{Reader: mgr.GetAPIReader()}
+	}
+	if enableDRA {
+		metricsCollector.DeviceSource = &metrics.DRADeviceSource{Reader: mgr.GetClient()}
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+	metricsCollector.ServerVersion = discoveryClient
+	metricsCollector.APIReader = mgr.GetAPIReader()
+
+	// Node and pod totals are maintained from informer events instead of listed on every reconcile
+	metricsCollector.Aggregator = metrics.NewAggregator(metricsCollector, mgr.GetCache(), aggregatorResyncInterval)
+	if err := mgr.Add(metricsCollector.Aggregator); err != nil {
+		setupLog.Error(err, "unable to add resource aggregator")
+		os.Exit(1)
+	}
+
+	var energyRefresher *carbon.Refresher
+	if energyProvider != "" {
+		provider, err := newEnergyProvider(energyProvider, energySource, energyCarbonIntensity, energyCost, energyCurrency)
+		if err != nil {
+			setupLog.Error(err, "invalid energy provider configuration", "energy-provider", energyProvider)
+			os.Exit(1)
+		}
+		energyRefresher = carbon.NewRefresher(energyProvider, provider, energyRefreshInterval)
+		if err := mgr.Add(energyRefresher); err != nil {
+			setupLog.Error(err, "unable to add energy refresher")
+			os.Exit(1)
+		}
+	}
+
+	var forecaster *forecast.Forecaster
+	if enableForecast {
+		// The history ConfigMap is only read at startup, so reads bypass the cache
+		forecaster = forecast.NewForecaster(mgr.GetClient(), mgr.GetAPIReader(), types.NamespacedName{
+			Name:      advertisementName + "-forecast",
+			Namespace: forecastNamespace,
+		}, forecastHistory)
+	}
+
+	if err = (&controller.AdvertisementReconciler{
+		Client:             mgr.GetClient(),
+		Scheme:             mgr.GetScheme(),
+		MetricsCollector:   metricsCollector,
 		BrokerClient:       brokerClient,       // Legacy Kubernetes transport
 		BrokerCommunicator: brokerCommunicator, // New transport abstraction (HTTP)
 		RequeueInterval:    advertisementRequeueInterval,
+		EnergyRefresher:    energyRefresher,
+		Forecaster:         forecaster,
+		WithdrawOnShutdown: withdrawOnShutdown,
+		WithdrawOnDelete:   withdrawOnDelete,
 		TargetKey: types.NamespacedName{
 			Name:      advertisementName,
 			Namespace: advertisementNamespace,
@@ -435,6 +618,60 @@ func defaultResourceMetrics() rearv1alpha1.ResourceMetrics {
 	}
 }
 
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// newEnergyProvider creates the carbon.Provider selected by --energy-provider
+func newEnergyProvider(kind, source, carbonIntensity, cost, currency string) (carbon.Provider, error) {
+	switch kind {
+	case carbon.ProviderStatic:
+		reading := carbon.Reading{Currency: currency}
+		var err error
+		if reading.CarbonIntensity, err = parseOptionalFloat(carbonIntensity); err != nil {
+			return nil, fmt.Errorf("invalid carbon intensity: %w", err)
+		}
+		if reading.EnergyCost, err = parseOptionalFloat(cost); err != nil {
+			return nil, fmt.Errorf("invalid energy cost: %w", err)
+		}
+		return &carbon.StaticProvider{Reading: reading}, nil
+	case carbon.ProviderFile:
+		if source == "" {
+			return nil, fmt.Errorf("--energy-source is required for the file provider")
+		}
+		return &carbon.FileProvider{Path: source}, nil
+	case carbon.ProviderHTTP:
+		if source == "" {
+			return nil, fmt.Errorf("--energy-source is required for the http provider")
+		}
+		return &carbon.HTTPProvider{URL: source}, nil
+	default:
+		return nil, fmt.Errorf("unknown energy provider %q", kind)
+	}
+}
+
+// parseOptionalFloat parses a non-negative number, returning nil for an empty value
+func parseOptionalFloat(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	if parsed < 0 {
+		return nil, fmt.Errorf("negative value %s", value)
+	}
+	return &parsed, nil
+}
+
 // NewCommunicator creates a BrokerCommunicator based on transport type
 func NewCommunicator(
 	transportType string,
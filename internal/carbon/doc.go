@@ -0,0 +1,5 @@
+// Package carbon reads the carbon intensity and cost of the energy powering the
+// cluster from a pluggable provider, so that the broker can prefer greener clusters.
+// A Refresher polls the provider on its own schedule and the Advertisement
+// reconciler publishes the latest reading.
+package carbon
@@ -0,0 +1,112 @@
+package carbon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Provider names
+const (
+	ProviderStatic = "static"
+	ProviderFile   = "file"
+	ProviderHTTP   = "http"
+)
+
+// maxReadingSize bounds the size of a reading read from a file or an HTTP endpoint
+const maxReadingSize = 1 << 20
+
+// Reading is a carbon intensity and energy cost sample.
+// Files and HTTP endpoints serve it as JSON, e.g.
+// {"carbonIntensity": 212.5, "energyCost": 0.19, "currency": "EUR"}.
+type Reading struct {
+	// CarbonIntensity in gCO2eq per kWh
+	CarbonIntensity *float64 `json:"carbonIntensity,omitempty"`
+	// EnergyCost per kWh
+	EnergyCost *float64 `json:"energyCost,omitempty"`
+	// Currency of EnergyCost
+	Currency string `json:"currency,omitempty"`
+	// ObservedAt is when the sample was taken; the read time is used when unset
+	ObservedAt *time.Time `json:"observedAt,omitempty"`
+}
+
+// Provider returns the current energy reading
+type Provider interface {
+	// Read returns the latest reading
+	Read(ctx context.Context) (*Reading, error)
+}
+
+// StaticProvider always returns the same configured reading
+type StaticProvider struct {
+	Reading Reading
+}
+
+// Read returns the configured reading
+func (p *StaticProvider) Read(_ context.Context) (*Reading, error) {
+	reading := p.Reading
+	return &reading, nil
+}
+
+// FileProvider reads a JSON reading from a file, e.g. one written by a sidecar or mounted from a ConfigMap
+type FileProvider struct {
+	Path string
+}
+
+// Read parses the file
+func (p *FileProvider) Read(_ context.Context) (*Reading, error) {
+	file, err := os.Open(p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open energy reading file: %w", err)
+	}
+	defer file.Close()
+
+	return decodeReading(file)
+}
+
+// HTTPProvider fetches a JSON reading from a local HTTP endpoint
+type HTTPProvider struct {
+	URL    string
+	Client *http.Client
+}
+
+// Read performs a GET request on the endpoint
+func (p *HTTPProvider) Read(ctx context.Context) (*Reading, error) {
+	httpClient := p.Client
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch energy reading: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("energy endpoint returned status %d", resp.StatusCode)
+	}
+
+	return decodeReading(resp.Body)
+}
+
+// decodeReading parses a JSON reading
+func decodeReading(r io.Reader) (*Reading, error) {
+	reading := &Reading{}
+	if err := json.NewDecoder(io.LimitReader(r, maxReadingSize)).Decode(reading); err != nil {
+		return nil, fmt.Errorf("failed to decode energy reading: %w", err)
+	}
+	if (reading.CarbonIntensity != nil && *reading.CarbonIntensity < 0) || (reading.EnergyCost != nil && *reading.EnergyCost < 0) {
+		return nil, fmt.Errorf("energy reading has negative values")
+	}
+	return reading, nil
+}
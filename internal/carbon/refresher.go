@@ -0,0 +1,101 @@
+package carbon
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// Refresher polls a Provider on its own schedule and keeps the latest reading.
+// A failed refresh keeps the previous reading, whose ObservedAt tells its age.
+type Refresher struct {
+	source   string
+	provider Provider
+	interval time.Duration
+
+	mu     sync.RWMutex
+	latest *rearv1alpha1.EnergyInfo
+}
+
+// NewRefresher creates a refresher for the named provider.
+// It must be added to the manager (mgr.Add) to start polling.
+func NewRefresher(source string, provider Provider, interval time.Duration) *Refresher {
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	return &Refresher{
+		source:   source,
+		provider: provider,
+		interval: interval,
+	}
+}
+
+// Start refreshes the reading immediately and then periodically until ctx is cancelled
+func (r *Refresher) Start(ctx context.Context) error {
+	r.refresh(ctx)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.refresh(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection returns false: every replica keeps its reading up to date
+func (r *Refresher) NeedLeaderElection() bool {
+	return false
+}
+
+// Latest returns a copy of the latest reading, or nil if none succeeded yet
+func (r *Refresher) Latest() *rearv1alpha1.EnergyInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.latest.DeepCopy()
+}
+
+// refresh reads the provider and stores the result
+func (r *Refresher) refresh(ctx context.Context) {
+	logger := log.FromContext(ctx).WithName("energy-refresher")
+
+	reading, err := r.provider.Read(ctx)
+	if err != nil {
+		logger.Error(err, "failed to refresh energy reading", "source", r.source)
+		return
+	}
+
+	info := &rearv1alpha1.EnergyInfo{
+		CarbonIntensity: formatValue(reading.CarbonIntensity),
+		EnergyCost:      formatValue(reading.EnergyCost),
+		Currency:        reading.Currency,
+		Source:          r.source,
+		ObservedAt:      metav1.Now(),
+	}
+	if reading.ObservedAt != nil {
+		info.ObservedAt = metav1.NewTime(*reading.ObservedAt)
+	}
+
+	r.mu.Lock()
+	r.latest = info
+	r.mu.Unlock()
+}
+
+// formatValue renders an optional reading value as a decimal string
+func formatValue(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
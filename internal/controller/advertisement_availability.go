@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// migExtendedPrefix is the extended resource prefix of the MIG devices of a profile.
+// MIG devices are always advertised in Extended, which gives the limit of each profile.
+const migExtendedPrefix = "nvidia.com/mig-"
+
+// capAvailability bounds the availability of every breakdown of spec by the cluster-wide
+// Available, after the AdvertisementPolicy, lending schedule, offload quota or maintenance
+// lowered it: no node pool, node, GPU model or StorageClass can offer more than the cluster
+// as a whole. When nothing is lent, the
+// breakdowns without a cluster-wide counterpart (DRA devices, preemptible capacity) are
+// withdrawn as well.
+func capAvailability(spec *rearv1alpha1.AdvertisementSpec, lending bool) {
+	limit := spec.Resources.Available
+
+	for i := range spec.NodePools {
+		capQuantities(&spec.NodePools[i].Available, limit, lending)
+	}
+
+	if fragmentation := spec.Fragmentation; fragmentation != nil {
+		capQuantities(&fragmentation.LargestSchedulable, limit, lending)
+		capChunks(fragmentation.CPUChunks, limit.CPU, lending)
+		capChunks(fragmentation.MemoryChunks, limit.Memory, lending)
+	}
+
+	for i := range spec.GPUs {
+		gpu := &spec.GPUs[i]
+		capQuantity(&gpu.Available, limit.GPU, lending)
+		for j := range gpu.MIGProfiles {
+			profile := &gpu.MIGProfiles[j]
+			capQuantity(&profile.Available, extendedLimit(limit, migExtendedPrefix+profile.Profile), lending)
+		}
+	}
+
+	for i := range spec.StorageClasses {
+		if available := spec.StorageClasses[i].Available; available != nil {
+			capQuantity(available, limit.Storage, lending)
+		}
+	}
+
+	if lending {
+		return
+	}
+	for i := range spec.Resources.Devices {
+		spec.Resources.Devices[i].Available = 0
+	}
+	if spec.Resources.Preemptible != nil {
+		preemptible := zeroQuantities(*spec.Resources.Preemptible)
+		spec.Resources.Preemptible = &preemptible
+	}
+}
+
+// capQuantities bounds each quantity of rq by the same resource in limit,
+// or zeroes it when nothing is lent
+func capQuantities(rq *rearv1alpha1.ResourceQuantities, limit rearv1alpha1.ResourceQuantities, lending bool) {
+	if !lending {
+		*rq = zeroQuantities(*rq)
+		return
+	}
+
+	capQuantity(&rq.CPU, &limit.CPU, lending)
+	capQuantity(&rq.Memory, &limit.Memory, lending)
+	if rq.GPU != nil {
+		capQuantity(rq.GPU, limit.GPU, lending)
+	}
+	if rq.Storage != nil {
+		capQuantity(rq.Storage, limit.Storage, lending)
+	}
+	if rq.EphemeralStorage != nil {
+		capQuantity(rq.EphemeralStorage, limit.EphemeralStorage, lending)
+	}
+	if rq.Pods != nil {
+		capQuantity(rq.Pods, limit.Pods, lending)
+	}
+	for name, quantity := range rq.Extended {
+		capQuantity(&quantity, extendedLimit(limit, name), lending)
+		rq.Extended[name] = quantity
+	}
+}
+
+// capQuantity lowers q to limit when it is larger, or to zero when nothing is lent.
+// A nil limit means the resource is not advertised cluster-wide and leaves q untouched.
+func capQuantity(q *resource.Quantity, limit *resource.Quantity, lending bool) {
+	switch {
+	case !lending:
+		*q = *resource.NewQuantity(0, q.Format)
+	case limit != nil && q.Cmp(*limit) > 0:
+		*q = limit.DeepCopy()
+	}
+}
+
+// extendedLimit returns the cluster-wide amount of an extended resource, or nil if it is not advertised
+func extendedLimit(limit rearv1alpha1.ResourceQuantities, name string) *resource.Quantity {
+	quantity, ok := limit.Extended[name]
+	if !ok {
+		return nil
+	}
+	return &quantity
+}
+
+// capChunks moves the nodes counted in the buckets above limit to the bucket holding it,
+// as if the free amount of every node were bounded by limit (zero when nothing is lent)
+func capChunks(buckets []rearv1alpha1.FreeChunkBucket, limit resource.Quantity, lending bool) {
+	if !lending {
+		limit = *resource.NewQuantity(0, limit.Format)
+	}
+	target := -1
+	for i := range buckets {
+		if buckets[i].LowerBound.Cmp(limit) <= 0 {
+			target = i
+		}
+	}
+	if target < 0 {
+		return
+	}
+	for i := target + 1; i < len(buckets); i++ {
+		buckets[target].Nodes += buckets[i].Nodes
+		buckets[i].Nodes = 0
+	}
+}
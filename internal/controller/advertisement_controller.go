@@ -18,20 +18,31 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+	"github.com/mehdiazizian/liqo-resource-agent/internal/carbon"
+	"github.com/mehdiazizian/liqo-resource-agent/internal/forecast"
 	"github.com/mehdiazizian/liqo-resource-agent/internal/metrics"
+	"github.com/mehdiazizian/liqo-resource-agent/internal/policy"
+	"github.com/mehdiazizian/liqo-resource-agent/internal/pricing"
 	"github.com/mehdiazizian/liqo-resource-agent/internal/publisher" // ← Add this line
 	"github.com/mehdiazizian/liqo-resource-agent/internal/transport"
 	"github.com/mehdiazizian/liqo-resource-agent/internal/transport/dto"
@@ -46,14 +57,37 @@ type AdvertisementReconciler struct {
 	BrokerClient       *publisher.BrokerClient      // Legacy Kubernetes transport
 	BrokerCommunicator transport.BrokerCommunicator // New transport abstraction
 	TargetKey          types.NamespacedName
-	RequeueInterval    time.Duration // Configurable requeue interval
+	RequeueInterval    time.Duration        // Configurable requeue interval
+	EnergyRefresher    *carbon.Refresher    // Optional carbon intensity and energy cost source
+	Forecaster         *forecast.Forecaster // Optional availability forecasting
+	WithdrawOnShutdown bool                 // Withdraw the advertisement from the broker when the manager stops
+	WithdrawOnDelete   bool                 // Withdraw the advertisement from the broker before the Advertisement is deleted
+
+	// publishedMu serializes publishing with the withdrawal on shutdown
+	publishedMu        sync.Mutex
+	publishedClusterID string // Cluster ID last published to the broker, withdrawn on shutdown
+	shuttingDown       bool   // Set once the advertisement is withdrawn on shutdown; nothing is published after
 }
 
 // +kubebuilder:rbac:groups=rear.fluidos.eu,resources=advertisements,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rear.fluidos.eu,resources=advertisements/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=rear.fluidos.eu,resources=advertisements/finalizers,verbs=update
+// +kubebuilder:rbac:groups=rear.fluidos.eu,resources=advertisementpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=rear.fluidos.eu,resources=pricingpolicies,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// Usage is read from NodeMetrics only; metrics.MetricsServerUsageSource says why PodMetrics are not needed
+// +kubebuilder:rbac:groups=metrics.k8s.io,resources=nodes,verbs=get;list
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets;deployments,verbs=get;list
+// +kubebuilder:rbac:groups=resource.k8s.io,resources=deviceclasses;resourceslices;resourceclaims,verbs=get;list;watch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses;csistoragecapacities,verbs=get;list;watch
+
+// The forecast history ConfigMap lives in the agent namespace (--forecast-namespace defaults
+// to it), so access is granted by a Role there rather than on every ConfigMap of the cluster
+// +kubebuilder:rbac:groups="",namespace=system,resources=configmaps,verbs=get;create;update
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *AdvertisementReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -78,23 +112,155 @@ func (r *AdvertisementReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	// A deleted Advertisement is withdrawn from the broker before it goes away
+	if !advertisement.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, advertisement)
+	}
+	// The finalizer is only kept while delete-time withdrawal is wanted, so that Advertisements
+	// outliving the agent (e.g. on uninstall) are not left stuck in deletion
+	var finalizerChanged bool
+	if r.WithdrawOnDelete && r.brokerConfigured() {
+		finalizerChanged = controllerutil.AddFinalizer(advertisement, brokerWithdrawalFinalizer)
+	} else {
+		finalizerChanged = controllerutil.RemoveFinalizer(advertisement, brokerWithdrawalFinalizer)
+	}
+	if finalizerChanged {
+		if err := r.Update(ctx, advertisement); err != nil {
+			logger.Error(err, "failed to update finalizers",
+				"name", advertisement.Name,
+				"namespace", advertisement.Namespace)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Fetch the optional lending policy sharing the Advertisement's name
+	lendingPolicy, err := r.getAdvertisementPolicy(ctx, req.NamespacedName)
+	if err != nil {
+		logger.Error(err, "failed to get advertisement policy")
+		return r.collectionFailed(ctx, advertisement, fmt.Sprintf("Failed to get advertisement policy: %v", err))
+	}
+
+	// Find the lending window in effect, if the policy has a schedule
+	schedule, err := policy.EvaluateSchedule(lendingPolicy, time.Now())
+	if err != nil {
+		logger.Error(err, "failed to evaluate lending schedule")
+		return r.collectionFailed(ctx, advertisement, fmt.Sprintf("Failed to evaluate lending schedule: %v", err))
+	}
+
+	// Take one snapshot of nodes and pods, shared by every collector below
+	snapshot, err := r.MetricsCollector.Snapshot(ctx)
+	if err != nil {
+		logger.Error(err, "failed to take cluster snapshot")
+		return r.collectionFailed(ctx, advertisement, fmt.Sprintf("Failed to collect metrics: %v", err))
+	}
+
 	// Collect current cluster metrics
-	resourceData, err := r.MetricsCollector.CollectClusterResources(ctx)
+	resourceData, constraints, err := r.MetricsCollector.CollectClusterResources(ctx, snapshot, lendingPolicy, schedule)
 	if err != nil {
 		logger.Error(err, "failed to collect cluster resources")
-		return r.updateStatus(ctx, advertisement, "Error", false, fmt.Sprintf("Failed to collect metrics: %v", err))
+		return r.collectionFailed(ctx, advertisement, fmt.Sprintf("Failed to collect metrics: %v", err))
+	}
+
+	// In maintenance nothing new is lent; reservations already granted keep being honored
+	maintenance := advertisement.Spec.Maintenance != nil && advertisement.Spec.Maintenance.Enabled
+	if maintenance {
+		resourceData.Available = zeroQuantities(resourceData.Available)
+		for i := range constraints {
+			constraints[i].Constraint = rearv1alpha1.ConstraintMaintenance
+		}
 	}
 
 	// Get cluster ID
 	clusterID, err := r.MetricsCollector.GetClusterID(ctx)
 	if err != nil {
 		logger.Error(err, "failed to get cluster ID")
-		return r.updateStatus(ctx, advertisement, "Error", false, fmt.Sprintf("Failed to get cluster ID: %v", err))
+		return r.collectionFailed(ctx, advertisement, fmt.Sprintf("Failed to get cluster ID: %v", err))
+	}
+
+	// Collect actual usage; a missing metrics.k8s.io API only leaves Used empty
+	used, usageErr := r.MetricsCollector.CollectUsage(ctx, snapshot)
+	if usageErr != nil && !errors.Is(usageErr, metrics.ErrUsageMetricsUnavailable) {
+		logger.Error(usageErr, "failed to collect usage metrics")
+	}
+	resourceData.Used = used
+
+	// Collect DRA devices; a missing resource.k8s.io API only leaves Devices empty
+	devices, err := r.MetricsCollector.CollectDevices(ctx, snapshot)
+	if err != nil && !errors.Is(err, metrics.ErrDRAUnavailable) {
+		logger.Error(err, "failed to collect DRA devices")
+	}
+	resourceData.Devices = devices
+
+	// Collect the per-node-pool breakdown (nil when no pool label is configured)
+	nodePools := r.MetricsCollector.CollectNodePools(snapshot)
+
+	// Price the advertised resources; without a PricingPolicy no cost is advertised
+	pricingPolicy, err := r.getPricingPolicy(ctx, req.NamespacedName)
+	if err != nil {
+		logger.Error(err, "failed to get pricing policy")
+		return r.collectionFailed(ctx, advertisement, fmt.Sprintf("Failed to get pricing policy: %v", err))
+	}
+	var cost *rearv1alpha1.CostInfo
+	var pricingStatus *rearv1alpha1.PricingStatus
+	if pricingPolicy != nil {
+		if len(pricingPolicy.NodePools) > 0 && r.MetricsCollector.NodePoolLabel == "" {
+			logger.Info("node pool prices of the pricing policy are ignored: no node pool label is configured")
+		}
+		if cost, pricingStatus, err = pricing.Cost(pricingPolicy, resourceData, nodePools, time.Now()); err != nil {
+			logger.Error(err, "failed to compute cost")
+			return r.collectionFailed(ctx, advertisement, fmt.Sprintf("Failed to compute cost: %v", err))
+		}
+	}
+
+	// Record the availability and forecast it; a failed record only leaves the forecast stale.
+	// Maintenance periods are kept out of the history, which describes normal lending.
+	var forecasts []rearv1alpha1.AvailabilityForecast
+	if r.Forecaster != nil && !maintenance {
+		now := time.Now()
+		if err := r.Forecaster.Record(ctx, now, resourceData.Available); err != nil {
+			logger.Error(err, "failed to record availability history")
+		}
+		forecasts = r.Forecaster.Forecast(now)
+	}
+
+	// Count the ready nodes excluded by the node selection policy
+	excludedNodes := r.MetricsCollector.ExcludedNodeCount(snapshot)
+
+	// Collect single-node fragmentation figures
+	fragmentation := r.MetricsCollector.CollectFragmentation(snapshot)
+
+	// Collect the per-StorageClass breakdown (nil when storage is not advertised)
+	storageClasses, err := r.MetricsCollector.CollectStorageClasses(ctx)
+	if err != nil {
+		logger.Error(err, "failed to collect storage classes")
+		return r.collectionFailed(ctx, advertisement, fmt.Sprintf("Failed to collect storage classes: %v", err))
+	}
+
+	// Collect the GPU inventory (nil when no advertised node has GPUs)
+	gpus := r.MetricsCollector.CollectGPUs(snapshot)
+
+	// Collect capabilities; on failure the previously advertised ones are kept
+	capabilities, err := r.MetricsCollector.CollectCapabilities(ctx, snapshot)
+	if err != nil {
+		logger.Error(err, "failed to collect capabilities")
+		capabilities = advertisement.Spec.Capabilities
 	}
 
 	// Update the Advertisement spec with collected data
 	advertisement.Spec.ClusterID = clusterID
 	advertisement.Spec.Resources = *resourceData
+	advertisement.Spec.NodePools = nodePools
+	advertisement.Spec.Fragmentation = fragmentation
+	advertisement.Spec.StorageClasses = storageClasses
+	advertisement.Spec.Capabilities = capabilities
+	advertisement.Spec.GPUs = gpus
+	advertisement.Spec.Cost = cost
+	advertisement.Spec.Forecast = forecasts
+	// The breakdowns cannot offer more than the cluster as a whole, whatever lowered it
+	capAvailability(&advertisement.Spec, !maintenance && (schedule == nil || schedule.Lending))
+	if r.EnergyRefresher != nil {
+		advertisement.Spec.Energy = r.EnergyRefresher.Latest()
+	}
 	advertisement.Spec.Timestamp = metav1.Now()
 
 	// Update the Advertisement resource
@@ -105,6 +271,13 @@ func (r *AdvertisementReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	advertisement.Status.ExcludedNodes = excludedNodes
+	advertisement.Status.AvailabilityConstraints = constraints
+	advertisement.Status.Pricing = pricingStatus
+	advertisement.Status.Schedule = toLendingScheduleStatus(schedule)
+	r.setUsageCondition(advertisement, usageErr)
+	r.setNodePoolPricingCondition(advertisement, pricingPolicy)
+
 	// Log with better readability - single message with newlines
 	logger.Info(fmt.Sprintf("📊 Advertisement updated\n"+
 		"  └─ Cluster: %s\n"+
@@ -118,29 +291,157 @@ func (r *AdvertisementReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		resourceData.Allocated.Memory.String(),
 		resourceData.Available.Memory.String()))
 
-	// Update status to indicate successful publication
-	result, err := r.updateStatus(ctx, advertisement, "Active", true, "Advertisement updated successfully")
+	meta.SetStatusCondition(&advertisement.Status.Conditions, metav1.Condition{
+		Type:               rearv1alpha1.ConditionMetricsCollected,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Collected",
+		Message:            "Cluster resources collected",
+		ObservedGeneration: advertisement.Generation,
+	})
+
+	phase, message := "Active", "Advertisement updated successfully"
+	if maintenance {
+		phase, message = "Maintenance", "Advertisement withdrawn for maintenance"
+		if reason := advertisement.Spec.Maintenance.Reason; reason != "" {
+			message = fmt.Sprintf("%s: %s", message, reason)
+		}
+	}
 
-	// Publish to broker using new transport abstraction
-	if r.BrokerCommunicator != nil {
-		advDTO := dto.ToAdvertisementDTO(advertisement)
-		if err := r.BrokerCommunicator.PublishAdvertisement(ctx, advDTO); err != nil {
-			logger.Error(err, fmt.Sprintf("❌ Failed to publish to broker (will retry)\n  └─ Cluster: %s", clusterID))
-			// Don't fail the reconciliation, just log the error
+	// Publish before updating the status, so that it reports the actual outcome.
+	// A failed publish does not fail the reconciliation: it is retried on the next update.
+	published := false
+	if r.brokerConfigured() {
+		publishErr := r.publish(ctx, advertisement)
+		if errors.Is(publishErr, errShuttingDown) {
+			logger.Info("agent is shutting down, not publishing to broker", "clusterID", clusterID)
+			return ctrl.Result{}, nil
+		}
+		if publishErr != nil {
+			logger.Error(publishErr, fmt.Sprintf("❌ Failed to publish to broker (will retry)\n  └─ Cluster: %s", clusterID))
+			message = fmt.Sprintf("%s, but publishing to broker failed: %v", message, publishErr)
 		} else {
-			logger.Info(fmt.Sprintf("✅ Published to broker successfully (via transport abstraction)\n  └─ Cluster: %s", clusterID))
+			logger.Info(fmt.Sprintf("✅ Published to broker successfully\n  └─ Cluster: %s", clusterID))
 		}
-	} else if r.BrokerClient != nil && r.BrokerClient.Enabled {
+		r.setPublishStatus(ctx, advertisement, publishErr)
+		published = publishErr == nil
+	} else {
+		meta.SetStatusCondition(&advertisement.Status.Conditions, metav1.Condition{
+			Type:               rearv1alpha1.ConditionPublished,
+			Status:             metav1.ConditionFalse,
+			Reason:             "BrokerNotConfigured",
+			Message:            "No broker is configured",
+			ObservedGeneration: advertisement.Generation,
+		})
+		meta.RemoveStatusCondition(&advertisement.Status.Conditions, rearv1alpha1.ConditionBrokerReachable)
+	}
+
+	return r.updateStatus(ctx, advertisement, phase, published, message)
+}
+
+// publish sends the advertisement to the broker and records its cluster ID for the withdrawal
+// on shutdown. Once that withdrawal has started nothing is published anymore, and a publish
+// in flight completes before it, so the broker cannot be left with a re-published advertisement.
+func (r *AdvertisementReconciler) publish(ctx context.Context, advertisement *rearv1alpha1.Advertisement) error {
+	r.publishedMu.Lock()
+	defer r.publishedMu.Unlock()
+
+	if r.shuttingDown {
+		return errShuttingDown
+	}
+
+	var err error
+	if r.BrokerCommunicator != nil {
+		// Publish to broker using new transport abstraction
+		err = r.BrokerCommunicator.PublishAdvertisement(ctx, dto.ToAdvertisementDTO(advertisement))
+	} else {
 		// Legacy Kubernetes transport fallback
-		if err := r.BrokerClient.PublishAdvertisement(ctx, advertisement); err != nil {
-			logger.Error(err, fmt.Sprintf("❌ Failed to publish to broker (will retry)\n  └─ Cluster: %s", clusterID))
-			// Don't fail the reconciliation, just log the error
+		err = r.BrokerClient.PublishAdvertisement(ctx, advertisement)
+	}
+	if err != nil {
+		return err
+	}
+	r.publishedClusterID = advertisement.Spec.ClusterID
+	return nil
+}
+
+// setPublishStatus records the outcome of a publish in the Advertisement status
+func (r *AdvertisementReconciler) setPublishStatus(
+	ctx context.Context,
+	advertisement *rearv1alpha1.Advertisement,
+	publishErr error,
+) {
+	status := &advertisement.Status
+	published := metav1.Condition{
+		Type:               rearv1alpha1.ConditionPublished,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Published",
+		Message:            "Advertisement published to broker",
+		ObservedGeneration: advertisement.Generation,
+	}
+	reachable := metav1.Condition{
+		Type:               rearv1alpha1.ConditionBrokerReachable,
+		Status:             metav1.ConditionTrue,
+		Reason:             "BrokerResponded",
+		Message:            "Broker answered the last publish",
+		ObservedGeneration: advertisement.Generation,
+	}
+
+	if publishErr == nil {
+		now := metav1.Now()
+		status.LastPublishTime = &now
+		status.LastError = ""
+		status.ConsecutiveFailures = 0
+	} else {
+		status.LastError = publishErr.Error()
+		status.ConsecutiveFailures++
+
+		published.Status = metav1.ConditionFalse
+		published.Reason = "PublishFailed"
+		published.Message = publishErr.Error()
+		if reachErr := r.checkBrokerReachable(ctx, publishErr); reachErr != nil {
+			published.Reason = "BrokerUnreachable"
+			reachable.Status = metav1.ConditionFalse
+			reachable.Reason = "BrokerUnreachable"
+			reachable.Message = reachErr.Error()
 		} else {
-			logger.Info(fmt.Sprintf("✅ Published to broker successfully (via legacy client)\n  └─ Cluster: %s", clusterID))
+			reachable.Message = "Broker is reachable but rejected the advertisement"
 		}
 	}
 
-	return result, err
+	meta.SetStatusCondition(&status.Conditions, published)
+	meta.SetStatusCondition(&status.Conditions, reachable)
+}
+
+// checkBrokerReachable tells, after a failed publish, whether the broker could be reached.
+// It returns nil when the broker answered, and the reason it could not be reached otherwise.
+func (r *AdvertisementReconciler) checkBrokerReachable(ctx context.Context, publishErr error) error {
+	if r.BrokerCommunicator != nil {
+		return r.BrokerCommunicator.Ping(ctx)
+	}
+	// The legacy client reached the broker API server if it returned an API status
+	var apiStatus apierrors.APIStatus
+	if errors.As(publishErr, &apiStatus) {
+		return nil
+	}
+	return publishErr
+}
+
+// collectionFailed reports that the Advertisement could not be built from the cluster state
+func (r *AdvertisementReconciler) collectionFailed(
+	ctx context.Context,
+	advertisement *rearv1alpha1.Advertisement,
+	message string,
+) (ctrl.Result, error) {
+	meta.SetStatusCondition(&advertisement.Status.Conditions, metav1.Condition{
+		Type:               rearv1alpha1.ConditionMetricsCollected,
+		Status:             metav1.ConditionFalse,
+		Reason:             "CollectionFailed",
+		Message:            message,
+		ObservedGeneration: advertisement.Generation,
+	})
+	// Nothing was published, so the broker still holds the last published advertisement
+	// and the published flag, Published condition and LastPublishTime keep describing it
+	return r.updateStatus(ctx, advertisement, "Error", advertisement.Status.Published, message)
 }
 
 // updateStatus updates the Advertisement status
@@ -183,6 +484,131 @@ func (r *AdvertisementReconciler) updateStatus(
 	return ctrl.Result{RequeueAfter: waitDuration}, nil
 }
 
+// getAdvertisementPolicy returns the spec of the AdvertisementPolicy with the given key, or nil if there is none
+func (r *AdvertisementReconciler) getAdvertisementPolicy(
+	ctx context.Context,
+	key types.NamespacedName,
+) (*rearv1alpha1.AdvertisementPolicySpec, error) {
+	advertisementPolicy := &rearv1alpha1.AdvertisementPolicy{}
+	if err := r.Get(ctx, key, advertisementPolicy); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return &advertisementPolicy.Spec, nil
+}
+
+// getPricingPolicy returns the spec of the PricingPolicy with the given key, or nil if there is none
+func (r *AdvertisementReconciler) getPricingPolicy(
+	ctx context.Context,
+	key types.NamespacedName,
+) (*rearv1alpha1.PricingPolicySpec, error) {
+	pricingPolicy := &rearv1alpha1.PricingPolicy{}
+	if err := r.Get(ctx, key, pricingPolicy); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return &pricingPolicy.Spec, nil
+}
+
+// zeroQuantities returns rq with every reported amount set to zero
+func zeroQuantities(rq rearv1alpha1.ResourceQuantities) rearv1alpha1.ResourceQuantities {
+	zero := func(q resource.Quantity) *resource.Quantity {
+		return resource.NewQuantity(0, q.Format)
+	}
+
+	result := rearv1alpha1.ResourceQuantities{
+		CPU:    *zero(rq.CPU),
+		Memory: *zero(rq.Memory),
+	}
+	if rq.GPU != nil {
+		result.GPU = zero(*rq.GPU)
+	}
+	if rq.Storage != nil {
+		result.Storage = zero(*rq.Storage)
+	}
+	if rq.EphemeralStorage != nil {
+		result.EphemeralStorage = zero(*rq.EphemeralStorage)
+	}
+	if rq.Pods != nil {
+		result.Pods = zero(*rq.Pods)
+	}
+	if len(rq.Extended) > 0 {
+		result.Extended = make(map[string]resource.Quantity, len(rq.Extended))
+		for name, quantity := range rq.Extended {
+			result.Extended[name] = *zero(quantity)
+		}
+	}
+	return result
+}
+
+// toLendingScheduleStatus converts the state of the lending schedule to the API representation
+func toLendingScheduleStatus(state *policy.ScheduleState) *rearv1alpha1.LendingScheduleStatus {
+	if state == nil {
+		return nil
+	}
+
+	status := &rearv1alpha1.LendingScheduleStatus{
+		Lending: state.Lending,
+		Window:  state.Window,
+	}
+	if state.NextTransition != nil {
+		next := metav1.NewTime(*state.NextTransition)
+		status.NextTransition = &next
+	}
+	return status
+}
+
+// setUsageCondition records whether Resources.Used could be populated
+func (r *AdvertisementReconciler) setUsageCondition(advertisement *rearv1alpha1.Advertisement, usageErr error) {
+	if r.MetricsCollector.UsageSource == nil {
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               rearv1alpha1.ConditionUsageMetricsAvailable,
+		Status:             metav1.ConditionTrue,
+		Reason:             "UsageCollected",
+		Message:            "Resource usage collected from metrics.k8s.io",
+		ObservedGeneration: advertisement.Generation,
+	}
+	switch {
+	case errors.Is(usageErr, metrics.ErrUsageMetricsUnavailable):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "MetricsAPIUnavailable"
+		condition.Message = usageErr.Error()
+	case usageErr != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "UsageCollectionFailed"
+		condition.Message = usageErr.Error()
+	}
+
+	meta.SetStatusCondition(&advertisement.Status.Conditions, condition)
+}
+
+// setNodePoolPricingCondition records whether the per node pool prices of the PricingPolicy apply
+func (r *AdvertisementReconciler) setNodePoolPricingCondition(
+	advertisement *rearv1alpha1.Advertisement,
+	pricingPolicy *rearv1alpha1.PricingPolicySpec,
+) {
+	if pricingPolicy == nil || len(pricingPolicy.NodePools) == 0 {
+		meta.RemoveStatusCondition(&advertisement.Status.Conditions, rearv1alpha1.ConditionNodePoolPricing)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               rearv1alpha1.ConditionNodePoolPricing,
+		Status:             metav1.ConditionTrue,
+		Reason:             "NodePoolPricesApplied",
+		Message:            fmt.Sprintf("Node pool prices apply to the pools of label %s", r.MetricsCollector.NodePoolLabel),
+		ObservedGeneration: advertisement.Generation,
+	}
+	if r.MetricsCollector.NodePoolLabel == "" {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NodePoolLabelNotSet"
+		condition.Message = "Node pool prices are ignored: set --node-pool-label to group nodes into pools"
+	}
+
+	meta.SetStatusCondition(&advertisement.Status.Conditions, condition)
+}
+
 // calculateNextClockSync calculates the next clock-synchronized time
 // For 1-minute interval: returns next minute boundary (e.g., 14:35:00, 14:36:00)
 // For other intervals: aligns to nearest interval boundary
@@ -219,16 +645,32 @@ func (r *AdvertisementReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}
 	r.MetricsCollector.Client = r.Client
 
-	return ctrl.NewControllerManagedBy(mgr).
+	if r.WithdrawOnShutdown && r.brokerConfigured() {
+		if err := mgr.Add(manager.RunnableFunc(r.withdrawOnShutdown)); err != nil {
+			return err
+		}
+	}
+
+	bldr := ctrl.NewControllerManagedBy(mgr).
 		For(&rearv1alpha1.Advertisement{}).
+		// A policy shares the name of its Advertisement, so it maps onto the same request
+		Watches(&rearv1alpha1.AdvertisementPolicy{}, &handler.EnqueueRequestForObject{}).
+		Watches(&rearv1alpha1.PricingPolicy{}, &handler.EnqueueRequestForObject{}).
 		Watches(
 			&corev1.Node{},
 			handler.EnqueueRequestsFromMapFunc(r.findAdvertisementsForNode),
-		).
-		Watches(
+		)
+
+	// With the incremental aggregator, pod changes are folded into running totals and
+	// published on the next periodic update instead of triggering a reconcile each
+	if r.MetricsCollector.Aggregator == nil {
+		bldr = bldr.Watches(
 			&corev1.Pod{},
 			handler.EnqueueRequestsFromMapFunc(r.findAdvertisementsForPod),
-		).
+		)
+	}
+
+	return bldr.
 		Named("advertisement").
 		Complete(r)
 }
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// brokerWithdrawalFinalizer keeps an Advertisement until it has been withdrawn from the broker.
+// It is only added with WithdrawOnDelete, since it needs a running agent to be released.
+const brokerWithdrawalFinalizer = "rear.fluidos.eu/broker-withdrawal"
+
+// shutdownWithdrawalTimeout bounds the withdrawal performed when the manager stops
+const shutdownWithdrawalTimeout = 10 * time.Second
+
+// errShuttingDown is returned by publish once the advertisement has been withdrawn on shutdown
+var errShuttingDown = errors.New("agent is shutting down")
+
+// brokerConfigured reports whether advertisements are published to a broker
+func (r *AdvertisementReconciler) brokerConfigured() bool {
+	return r.BrokerCommunicator != nil || (r.BrokerClient != nil && r.BrokerClient.Enabled)
+}
+
+// withdraw removes the advertisement of the cluster from the broker
+func (r *AdvertisementReconciler) withdraw(ctx context.Context, clusterID string) error {
+	if r.BrokerCommunicator != nil {
+		return r.BrokerCommunicator.WithdrawAdvertisement(ctx, clusterID)
+	}
+	if r.BrokerClient != nil && r.BrokerClient.Enabled {
+		// Legacy Kubernetes transport fallback
+		return r.BrokerClient.WithdrawAdvertisement(ctx)
+	}
+	return nil
+}
+
+// finalize withdraws a deleted Advertisement from the broker and releases its finalizer.
+// On failure the finalizer is kept and the withdrawal retried with backoff.
+func (r *AdvertisementReconciler) finalize(
+	ctx context.Context,
+	advertisement *rearv1alpha1.Advertisement,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(advertisement, brokerWithdrawalFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if advertisement.Spec.ClusterID != "" {
+		if err := r.withdraw(ctx, advertisement.Spec.ClusterID); err != nil {
+			logger.Error(err, "failed to withdraw advertisement from broker (will retry)",
+				"clusterID", advertisement.Spec.ClusterID)
+			return ctrl.Result{}, err
+		}
+		logger.Info("withdrew advertisement from broker", "clusterID", advertisement.Spec.ClusterID)
+	}
+	r.setPublishedClusterID("")
+
+	controllerutil.RemoveFinalizer(advertisement, brokerWithdrawalFinalizer)
+	if err := r.Update(ctx, advertisement); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// setPublishedClusterID records the cluster ID last published to the broker ("" when withdrawn)
+func (r *AdvertisementReconciler) setPublishedClusterID(clusterID string) {
+	r.publishedMu.Lock()
+	defer r.publishedMu.Unlock()
+	r.publishedClusterID = clusterID
+}
+
+// withdrawOnShutdown waits for the manager to stop and then withdraws the published
+// advertisement, so that the broker does not keep sending reservations to a cluster
+// without a running agent. As a leader election runnable, only the leader withdraws.
+// Reconciles still running are not waited for: the lock shared with publish lets a
+// publish in flight finish first and keeps later ones from re-publishing.
+func (r *AdvertisementReconciler) withdrawOnShutdown(ctx context.Context) error {
+	<-ctx.Done()
+
+	r.publishedMu.Lock()
+	defer r.publishedMu.Unlock()
+	r.shuttingDown = true
+
+	clusterID := r.publishedClusterID
+	if clusterID == "" {
+		return nil
+	}
+
+	// The manager context is cancelled, so the withdrawal gets its own deadline
+	withdrawCtx, cancel := context.WithTimeout(context.Background(), shutdownWithdrawalTimeout)
+	defer cancel()
+
+	logger := log.FromContext(ctx)
+	if err := r.withdraw(withdrawCtx, clusterID); err != nil {
+		logger.Error(err, "failed to withdraw advertisement from broker on shutdown", "clusterID", clusterID)
+		return nil
+	}
+	logger.Info("withdrew advertisement from broker on shutdown", "clusterID", clusterID)
+	r.publishedClusterID = ""
+	return nil
+}
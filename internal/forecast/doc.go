@@ -0,0 +1,9 @@
+// Package forecast keeps a rolling history of the advertised availability and
+// predicts the availability over the next hours, so that the broker does not
+// mistake a cluster that is idle now but busy later for a good long-term provider.
+//
+// The history is a ring buffer of fixed-resolution slots, kept in memory and
+// persisted in a ConfigMap so that it survives agent restarts. Forecasts combine
+// an exponentially weighted moving average of recent slots with the daily
+// seasonality of the history, when at least a day of it is available.
+package forecast
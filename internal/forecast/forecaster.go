@@ -0,0 +1,279 @@
+package forecast
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// Resolution is the length of a history slot
+const Resolution = 15 * time.Minute
+
+// historyKey is the ConfigMap entry holding the serialized history
+const historyKey = "history.json"
+
+// ewmaAlpha is the weight of the most recent slot in the moving average
+const ewmaAlpha = 0.3
+
+// season is the period of the seasonal component
+const season = 24 * time.Hour
+
+// Horizons are the forecast windows published in the Advertisement
+var Horizons = []time.Duration{time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+// Forecaster records the advertised availability and forecasts it over the Horizons
+type Forecaster struct {
+	client client.Client
+	reader client.Reader
+	key    types.NamespacedName
+	length time.Duration
+
+	mu      sync.Mutex
+	history *history
+	// loaded is set once the persisted history has been read; until then the history is
+	// only kept in memory and never persisted, so that it cannot overwrite the stored one
+	loaded bool
+}
+
+// NewForecaster creates a forecaster keeping length of history in the ConfigMap with the given key.
+// The reader should bypass the cache, since the ConfigMap is only read at startup.
+func NewForecaster(c client.Client, reader client.Reader, key types.NamespacedName, length time.Duration) *Forecaster {
+	if length < season {
+		length = season
+	}
+	return &Forecaster{
+		client: c,
+		reader: reader,
+		key:    key,
+		length: length,
+	}
+}
+
+// Record adds the availability observed at now to the history.
+// The history is loaded on first use and persisted whenever a new slot starts. When it
+// cannot be loaded, forecasting goes on from an in-memory history and the load is retried
+// on the next call; the slots recorded meanwhile are then merged into the persisted history.
+func (f *Forecaster) Record(ctx context.Context, now time.Time, available rearv1alpha1.ResourceQuantities) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.loaded {
+		persisted, err := f.load(ctx)
+		if err != nil {
+			if f.history == nil {
+				f.history = newHistory(f.length, Resolution)
+			}
+			f.history.record(now, available)
+			return fmt.Errorf("%w (forecasting from an in-memory history)", err)
+		}
+		if f.history != nil {
+			persisted.merge(f.history)
+		}
+		f.history = persisted
+		f.loaded = true
+	}
+
+	if !f.history.record(now, available) {
+		return nil
+	}
+	return f.persist(ctx)
+}
+
+// Forecast predicts, for each horizon, the lowest availability expected between now and the
+// end of the horizon. It returns nil until some history has been recorded.
+func (f *Forecaster) Forecast(now time.Time) []rearv1alpha1.AvailabilityForecast {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.history == nil {
+		return nil
+	}
+	samples := f.history.recorded()
+	if samples == 0 {
+		return nil
+	}
+
+	current := f.history.start(now)
+	cpu := f.newPredictor(current, func(s slot) (float64, bool) { return float64(s.CPU), true })
+	memory := f.newPredictor(current, func(s slot) (float64, bool) { return float64(s.Memory), true })
+	gpu := f.newPredictor(current, func(s slot) (float64, bool) { return float64(s.GPU), s.GPU >= 0 })
+	if cpu.level == nil {
+		// Nothing was recorded for the current slot
+		return nil
+	}
+
+	forecasts := make([]rearv1alpha1.AvailabilityForecast, 0, len(Horizons))
+	for _, horizon := range Horizons {
+		steps := int64(horizon / Resolution)
+
+		cpuValue, cpuSeasonal := cpu.lowest(steps)
+		memoryValue, memorySeasonal := memory.lowest(steps)
+		predicted := slot{CPU: int64(math.Round(cpuValue)), Memory: int64(math.Round(memoryValue)), GPU: -1}
+		seasonal := cpuSeasonal || memorySeasonal
+		if gpu.level != nil {
+			gpuValue, gpuSeasonal := gpu.lowest(steps)
+			predicted.GPU = int64(math.Round(gpuValue))
+			seasonal = seasonal || gpuSeasonal
+		}
+
+		method := rearv1alpha1.ForecastMethodEWMA
+		if seasonal {
+			method = rearv1alpha1.ForecastMethodSeasonal
+		}
+		forecasts = append(forecasts, rearv1alpha1.AvailabilityForecast{
+			Horizon:   metav1.Duration{Duration: horizon},
+			Available: predicted.quantities(),
+			Method:    method,
+			Samples:   int32(samples),
+		})
+	}
+	return forecasts
+}
+
+// predictor forecasts one resource from the history
+type predictor struct {
+	history *history
+	current int64
+	value   func(slot) (float64, bool)
+	// level is the moving average up to the current slot, nil when the resource has no history
+	level *float64
+}
+
+// newPredictor computes the moving average of a resource over the history ending at current.
+// The average only starts when the resource is present in the current slot, so that a
+// resource no longer advertised is not forecast.
+func (f *Forecaster) newPredictor(current int64, value func(slot) (float64, bool)) *predictor {
+	p := &predictor{history: f.history, current: current, value: value}
+
+	latest, ok := f.history.get(current)
+	if !ok {
+		return p
+	}
+	if _, ok := value(latest); !ok {
+		return p
+	}
+
+	resolution := f.history.Resolution
+	oldest := current - int64(len(f.history.Slots)-1)*resolution
+	for start := oldest; start <= current; start += resolution {
+		s, ok := f.history.get(start)
+		if !ok {
+			continue
+		}
+		v, ok := value(s)
+		if !ok {
+			continue
+		}
+		if p.level == nil {
+			p.level = &v
+			continue
+		}
+		level := ewmaAlpha*v + (1-ewmaAlpha)**p.level
+		p.level = &level
+	}
+	return p
+}
+
+// lowest returns the lowest availability predicted over the next steps slots, and whether
+// the seasonal component could be applied. Each step adds to the moving average the
+// difference between the seasonal profile of that slot and of the current one.
+func (p *predictor) lowest(steps int64) (float64, bool) {
+	if p.level == nil {
+		return 0, false
+	}
+
+	lowest := *p.level
+	base, seasonal := p.seasonal(p.current)
+	used := false
+	if seasonal {
+		for step := int64(1); step <= steps; step++ {
+			profile, ok := p.seasonal(p.current + step*p.history.Resolution)
+			if !ok {
+				continue
+			}
+			used = true
+			lowest = math.Min(lowest, *p.level+profile-base)
+		}
+	}
+	return math.Max(0, lowest), used
+}
+
+// seasonal averages the values recorded at the same time of day on previous days
+func (p *predictor) seasonal(start int64) (float64, bool) {
+	period := int64(season / time.Second)
+	sum, count := 0.0, 0
+	for past := start - period; past > p.current-int64(len(p.history.Slots))*p.history.Resolution; past -= period {
+		s, ok := p.history.get(past)
+		if !ok {
+			continue
+		}
+		if v, ok := p.value(s); ok {
+			sum += v
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
+
+// load reads the persisted history, starting over when it is missing or was recorded with another layout
+func (f *Forecaster) load(ctx context.Context) (*history, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := f.reader.Get(ctx, f.key, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return newHistory(f.length, Resolution), nil
+		}
+		return nil, fmt.Errorf("failed to get forecast history: %w", err)
+	}
+
+	persisted := &history{}
+	if err := json.Unmarshal([]byte(configMap.Data[historyKey]), persisted); err != nil || !persisted.compatible(f.length, Resolution) {
+		return newHistory(f.length, Resolution), nil
+	}
+	return persisted, nil
+}
+
+// persist stores the history in the ConfigMap, creating it if needed
+func (f *Forecaster) persist(ctx context.Context) error {
+	data, err := json.Marshal(f.history)
+	if err != nil {
+		return fmt.Errorf("failed to encode forecast history: %w", err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := f.reader.Get(ctx, f.key, configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get forecast history: %w", err)
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: f.key.Name, Namespace: f.key.Namespace},
+			Data:       map[string]string{historyKey: string(data)},
+		}
+		if err := f.client.Create(ctx, configMap); err != nil {
+			return fmt.Errorf("failed to create forecast history: %w", err)
+		}
+		return nil
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[historyKey] = string(data)
+	if err := f.client.Update(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to update forecast history: %w", err)
+	}
+	return nil
+}
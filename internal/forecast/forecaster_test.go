@@ -0,0 +1,173 @@
+package forecast
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestPredictorLowest(t *testing.T) {
+	current := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		// cpu maps the offset of a slot from current to the cores recorded in it
+		cpu          map[time.Duration]int64
+		steps        int64
+		want         float64
+		wantSeasonal bool
+	}{
+		{
+			name:  "flat history forecasts the current level",
+			cpu:   map[time.Duration]int64{-2 * time.Hour: 10, -time.Hour: 10, 0: 10},
+			steps: 4,
+			want:  10000,
+		},
+		{
+			name: "moving average weighs the most recent slot by alpha",
+			cpu:  map[time.Duration]int64{-time.Hour: 10, 0: 20},
+			// 0.3*20 + 0.7*10
+			steps: 4,
+			want:  13000,
+		},
+		{
+			name: "moving average skips missing slots",
+			cpu:  map[time.Duration]int64{-5 * time.Hour: 10, -time.Hour: 20, 0: 30},
+			// 0.3*20 + 0.7*10 = 13, then 0.3*30 + 0.7*13
+			steps: 4,
+			want:  18100,
+		},
+		{
+			name: "seasonal profile lowers the forecast ahead of a daily dip",
+			cpu:  map[time.Duration]int64{-24 * time.Hour: 100, -23 * time.Hour: 40, 0: 100},
+			// level: 100, then 82, then 87.4; the dip one hour ahead is 60 below the current slot
+			steps:        4,
+			want:         27400,
+			wantSeasonal: true,
+		},
+		{
+			name: "daily dip beyond the horizon only weighs on the moving average",
+			cpu:  map[time.Duration]int64{-24 * time.Hour: 100, -21 * time.Hour: 40, 0: 100},
+			// no slot of the previous day falls within the next two hours
+			steps: 2,
+			want:  87400,
+		},
+		{
+			name:         "seasonal forecast never goes below zero",
+			cpu:          map[time.Duration]int64{-24 * time.Hour: 100, -23 * time.Hour: 0, 0: 10},
+			steps:        4,
+			want:         0,
+			wantSeasonal: true,
+		},
+		{
+			name:  "nothing recorded for the current slot",
+			cpu:   map[time.Duration]int64{-time.Hour: 10},
+			steps: 4,
+			want:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHistory(48*time.Hour, time.Hour)
+			for offset, cores := range tt.cpu {
+				h.record(current.Add(offset), available(strconv.FormatInt(cores, 10), "1Gi", ""))
+			}
+
+			f := &Forecaster{history: h}
+			p := f.newPredictor(h.start(current), func(s slot) (float64, bool) { return float64(s.CPU), true })
+
+			got, seasonal := p.lowest(tt.steps)
+			if math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("lowest(%d) = %f, want %f", tt.steps, got, tt.want)
+			}
+			if seasonal != tt.wantSeasonal {
+				t.Errorf("lowest(%d) seasonal = %t, want %t", tt.steps, seasonal, tt.wantSeasonal)
+			}
+		})
+	}
+}
+
+func TestForecasterLoadResetsIncompatibleHistory(t *testing.T) {
+	key := types.NamespacedName{Namespace: "agent", Name: "adv-forecast"}
+	base := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+
+	stale := newHistory(24*time.Hour, 5*time.Minute)
+	stale.record(base, available("4", "4Gi", ""))
+	data, err := json.Marshal(stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Data:       map[string]string{historyKey: string(data)},
+	}).Build()
+	f := NewForecaster(c, c, key, 24*time.Hour)
+
+	loaded, err := f.load(context.Background())
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !loaded.compatible(24*time.Hour, Resolution) {
+		t.Errorf("loaded history has resolution %ds and %d slots", loaded.Resolution, len(loaded.Slots))
+	}
+	if got := loaded.recorded(); got != 0 {
+		t.Errorf("incompatible history was reused: %d slots recorded", got)
+	}
+}
+
+func TestForecasterRecordFallsBackToMemory(t *testing.T) {
+	key := types.NamespacedName{Namespace: "agent", Name: "adv-forecast"}
+	base := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+
+	forbidden := true
+	c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if forbidden {
+				return apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, key.Name, nil)
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}).Build()
+	f := NewForecaster(c, c, key, 24*time.Hour)
+
+	if err := f.Record(context.Background(), base, available("4", "4Gi", "")); err == nil {
+		t.Fatalf("Record succeeded although the history could not be loaded")
+	}
+	if forecasts := f.Forecast(base); len(forecasts) != len(Horizons) {
+		t.Fatalf("Forecast returned %d forecasts from the in-memory history, want %d", len(forecasts), len(Horizons))
+	}
+	if err := c.Get(context.Background(), key, &corev1.ConfigMap{}); !apierrors.IsForbidden(err) {
+		t.Fatalf("expected the ConfigMap to be unreadable, got %v", err)
+	}
+
+	forbidden = false
+	if err := f.Record(context.Background(), base.Add(Resolution), available("2", "2Gi", "")); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(context.Background(), key, configMap); err != nil {
+		t.Fatalf("history was not persisted: %v", err)
+	}
+	persisted := &history{}
+	if err := json.Unmarshal([]byte(configMap.Data[historyKey]), persisted); err != nil {
+		t.Fatalf("decode persisted history: %v", err)
+	}
+	if got := persisted.recorded(); got != 2 {
+		t.Errorf("persisted history holds %d slots, want the in-memory one and the new one", got)
+	}
+}
@@ -0,0 +1,132 @@
+package forecast
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// slot holds the lowest availability observed during one resolution interval.
+// Field names are short because the whole buffer is stored in a ConfigMap.
+type slot struct {
+	// Start of the interval as Unix seconds; 0 marks an empty slot
+	Start int64 `json:"t"`
+	// CPU in millicores
+	CPU int64 `json:"c"`
+	// Memory in bytes
+	Memory int64 `json:"m"`
+	// GPU in millis, -1 when the cluster advertised no GPU
+	GPU int64 `json:"g"`
+}
+
+// history is a ring buffer indexed by time: the slot of an interval is its
+// index modulo the buffer length, so older intervals are overwritten in place
+type history struct {
+	Resolution int64  `json:"resolution"` // Seconds
+	Slots      []slot `json:"slots"`
+}
+
+// newHistory creates an empty buffer covering length at the given resolution
+func newHistory(length, resolution time.Duration) *history {
+	size := int(length / resolution)
+	if size < 1 {
+		size = 1
+	}
+	return &history{
+		Resolution: int64(resolution / time.Second),
+		Slots:      make([]slot, size),
+	}
+}
+
+// compatible reports whether a persisted buffer can be reused with the given layout
+func (h *history) compatible(length, resolution time.Duration) bool {
+	return h.Resolution == int64(resolution/time.Second) && len(h.Slots) == int(length/resolution)
+}
+
+// start returns the start of the interval containing t
+func (h *history) start(t time.Time) int64 {
+	unix := t.Unix()
+	return unix - unix%h.Resolution
+}
+
+// get returns the slot of the interval starting at start, if it was recorded
+func (h *history) get(start int64) (slot, bool) {
+	s := h.Slots[h.index(start)]
+	return s, s.Start == start && start != 0
+}
+
+// record lowers the availability of the interval containing t to the given one.
+// It reports whether a new interval was started.
+func (h *history) record(t time.Time, available rearv1alpha1.ResourceQuantities) bool {
+	start := h.start(t)
+	sample := slot{
+		Start:  start,
+		CPU:    max(0, available.CPU.MilliValue()),
+		Memory: max(0, available.Memory.Value()),
+		GPU:    -1,
+	}
+	if available.GPU != nil {
+		sample.GPU = max(0, available.GPU.MilliValue())
+	}
+
+	i := h.index(start)
+	current := h.Slots[i]
+	if current.Start != start {
+		h.Slots[i] = sample
+		return true
+	}
+
+	// Keeping the minimum makes the forecast conservative within an interval
+	current.CPU = min(current.CPU, sample.CPU)
+	current.Memory = min(current.Memory, sample.Memory)
+	if current.GPU >= 0 && sample.GPU >= 0 {
+		current.GPU = min(current.GPU, sample.GPU)
+	} else {
+		current.GPU = max(current.GPU, sample.GPU)
+	}
+	h.Slots[i] = current
+	return false
+}
+
+// merge copies into h the slots of other that are more recent than the ones they replace
+func (h *history) merge(other *history) {
+	for _, s := range other.Slots {
+		if s.Start == 0 {
+			continue
+		}
+		i := h.index(s.Start)
+		if h.Slots[i].Start <= s.Start {
+			h.Slots[i] = s
+		}
+	}
+}
+
+// recorded returns the number of slots holding data
+func (h *history) recorded() int {
+	count := 0
+	for _, s := range h.Slots {
+		if s.Start != 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// index returns the position of an interval in the buffer
+func (h *history) index(start int64) int {
+	return int((start / h.Resolution) % int64(len(h.Slots)))
+}
+
+// quantities converts a slot back to advertised quantities
+func (s slot) quantities() rearv1alpha1.ResourceQuantities {
+	quantities := rearv1alpha1.ResourceQuantities{
+		CPU:    *resource.NewMilliQuantity(s.CPU, resource.DecimalSI),
+		Memory: *resource.NewQuantity(s.Memory, resource.BinarySI),
+	}
+	if s.GPU >= 0 {
+		quantities.GPU = resource.NewMilliQuantity(s.GPU, resource.DecimalSI)
+	}
+	return quantities
+}
@@ -0,0 +1,176 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// available builds advertised quantities; gpu is omitted when empty
+func available(cpu, memory, gpu string) rearv1alpha1.ResourceQuantities {
+	quantities := rearv1alpha1.ResourceQuantities{
+		CPU:    resource.MustParse(cpu),
+		Memory: resource.MustParse(memory),
+	}
+	if gpu != "" {
+		g := resource.MustParse(gpu)
+		quantities.GPU = &g
+	}
+	return quantities
+}
+
+func TestHistoryRecord(t *testing.T) {
+	base := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+
+	type sample struct {
+		at        time.Duration
+		available rearv1alpha1.ResourceQuantities
+		newSlot   bool
+	}
+	tests := []struct {
+		name    string
+		samples []sample
+		// want is the slot expected at base, ok whether it is still recorded
+		want slot
+		ok   bool
+	}{
+		{
+			name: "first sample starts a slot",
+			samples: []sample{
+				{at: 0, available: available("4", "8Gi", ""), newSlot: true},
+			},
+			want: slot{CPU: 4000, Memory: 8 << 30, GPU: -1},
+			ok:   true,
+		},
+		{
+			name: "samples within a slot keep the minimum",
+			samples: []sample{
+				{at: 0, available: available("4", "4Gi", "2"), newSlot: true},
+				{at: 5 * time.Minute, available: available("2", "8Gi", "1"), newSlot: false},
+				{at: 14 * time.Minute, available: available("3", "6Gi", "3"), newSlot: false},
+			},
+			want: slot{CPU: 2000, Memory: 4 << 30, GPU: 1000},
+			ok:   true,
+		},
+		{
+			name: "a GPU appearing within a slot is recorded",
+			samples: []sample{
+				{at: 0, available: available("4", "4Gi", ""), newSlot: true},
+				{at: time.Minute, available: available("4", "4Gi", "2"), newSlot: false},
+			},
+			want: slot{CPU: 4000, Memory: 4 << 30, GPU: 2000},
+			ok:   true,
+		},
+		{
+			name: "negative availability is recorded as zero",
+			samples: []sample{
+				{at: 0, available: available("-1", "-1Gi", ""), newSlot: true},
+			},
+			want: slot{CPU: 0, Memory: 0, GPU: -1},
+			ok:   true,
+		},
+		{
+			name: "the next interval starts a new slot",
+			samples: []sample{
+				{at: 0, available: available("4", "4Gi", ""), newSlot: true},
+				{at: Resolution, available: available("1", "1Gi", ""), newSlot: true},
+			},
+			want: slot{CPU: 4000, Memory: 4 << 30, GPU: -1},
+			ok:   true,
+		},
+		{
+			name: "a full turn of the buffer overwrites the slot",
+			samples: []sample{
+				{at: 0, available: available("4", "4Gi", ""), newSlot: true},
+				{at: 24 * time.Hour, available: available("1", "1Gi", ""), newSlot: true},
+			},
+			ok: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHistory(24*time.Hour, Resolution)
+			for _, s := range tt.samples {
+				if got := h.record(base.Add(s.at), s.available); got != s.newSlot {
+					t.Errorf("record at +%s started a new slot = %t, want %t", s.at, got, s.newSlot)
+				}
+			}
+
+			got, ok := h.get(h.start(base))
+			if ok != tt.ok {
+				t.Fatalf("get(base) recorded = %t, want %t", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			tt.want.Start = h.start(base)
+			if got != tt.want {
+				t.Errorf("get(base) = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryGetEmptySlot(t *testing.T) {
+	h := newHistory(24*time.Hour, Resolution)
+	if _, ok := h.get(0); ok {
+		t.Errorf("get(0) on an empty history reported a recorded slot")
+	}
+	if _, ok := h.get(h.start(time.Now())); ok {
+		t.Errorf("get(now) on an empty history reported a recorded slot")
+	}
+}
+
+func TestHistoryCompatible(t *testing.T) {
+	h := newHistory(7*24*time.Hour, Resolution)
+
+	tests := []struct {
+		name       string
+		length     time.Duration
+		resolution time.Duration
+		want       bool
+	}{
+		{name: "same layout", length: 7 * 24 * time.Hour, resolution: Resolution, want: true},
+		{name: "shorter history", length: 24 * time.Hour, resolution: Resolution, want: false},
+		{name: "longer history", length: 14 * 24 * time.Hour, resolution: Resolution, want: false},
+		{name: "other resolution", length: 7 * 24 * time.Hour, resolution: 5 * time.Minute, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.compatible(tt.length, tt.resolution); got != tt.want {
+				t.Errorf("compatible(%s, %s) = %t, want %t", tt.length, tt.resolution, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryMerge(t *testing.T) {
+	base := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+
+	persisted := newHistory(24*time.Hour, Resolution)
+	persisted.record(base, available("4", "4Gi", ""))
+	persisted.record(base.Add(Resolution), available("4", "4Gi", ""))
+
+	memory := newHistory(24*time.Hour, Resolution)
+	// Older than the persisted slot it maps to: dropped
+	memory.record(base.Add(Resolution-24*time.Hour), available("9", "9Gi", ""))
+	// Newer than the persisted slot it maps to: kept
+	memory.record(base.Add(2*Resolution), available("1", "1Gi", ""))
+
+	persisted.merge(memory)
+
+	if got := persisted.recorded(); got != 3 {
+		t.Errorf("recorded() = %d, want 3", got)
+	}
+	if s, ok := persisted.get(persisted.start(base.Add(Resolution))); !ok || s.CPU != 4000 {
+		t.Errorf("persisted slot was replaced by an older one: %+v", s)
+	}
+	if s, ok := persisted.get(persisted.start(base.Add(2 * Resolution))); !ok || s.CPU != 1000 {
+		t.Errorf("in-memory slot was not merged: %+v", s)
+	}
+}
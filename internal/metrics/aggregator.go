@@ -0,0 +1,188 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Aggregator maintains running node and pod totals from informer events so that
+// CollectClusterResources does not have to list every node and pod on each reconcile.
+// A periodic full resync from the informer cache corrects any drift.
+type Aggregator struct {
+	collector      *Collector
+	cache          cache.Cache
+	resyncInterval time.Duration
+
+	mu     sync.RWMutex
+	state  *clusterState
+	synced bool
+}
+
+// NewAggregator creates an aggregator backed by the manager cache.
+// It must be added to the manager (mgr.Add) to start receiving events.
+func NewAggregator(collector *Collector, informers cache.Cache, resyncInterval time.Duration) *Aggregator {
+	if resyncInterval <= 0 {
+		resyncInterval = 10 * time.Minute
+	}
+	return &Aggregator{
+		collector:      collector,
+		cache:          informers,
+		resyncInterval: resyncInterval,
+		state:          newClusterState(collector),
+	}
+}
+
+// Start registers the informer handlers and runs the periodic resync until ctx is cancelled
+func (a *Aggregator) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("resource-aggregator")
+
+	nodeInformer, err := a.cache.GetInformer(ctx, &corev1.Node{})
+	if err != nil {
+		return fmt.Errorf("failed to get node informer: %w", err)
+	}
+	if _, err := nodeInformer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    a.onNode,
+		UpdateFunc: func(_, obj interface{}) { a.onNode(obj) },
+		DeleteFunc: a.onNodeDelete,
+	}); err != nil {
+		return fmt.Errorf("failed to register node handler: %w", err)
+	}
+
+	podInformer, err := a.cache.GetInformer(ctx, &corev1.Pod{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod informer: %w", err)
+	}
+	if _, err := podInformer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    a.onPod,
+		UpdateFunc: func(_, obj interface{}) { a.onPod(obj) },
+		DeleteFunc: a.onPodDelete,
+	}); err != nil {
+		return fmt.Errorf("failed to register pod handler: %w", err)
+	}
+
+	if !a.cache.WaitForCacheSync(ctx) {
+		return fmt.Errorf("failed to sync informer cache")
+	}
+
+	// The initial resync builds the totals from a consistent cache view
+	if err := a.resync(ctx); err != nil {
+		logger.Error(err, "initial resync failed")
+	}
+
+	ticker := time.NewTicker(a.resyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := a.resync(ctx); err != nil {
+				logger.Error(err, "periodic resync failed")
+			}
+		}
+	}
+}
+
+// resync rebuilds the totals from the informer cache
+func (a *Aggregator) resync(ctx context.Context) error {
+	// Holding the lock while rebuilding keeps events from being applied to the old state
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	state, err := a.collector.buildState(ctx, a.cache)
+	if err != nil {
+		return err
+	}
+
+	a.state = state
+	a.synced = true
+	return nil
+}
+
+// view returns the current cluster and per-node totals, or false if the first resync has not completed
+func (a *Aggregator) view() (*Snapshot, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if !a.synced {
+		return nil, false
+	}
+	return a.state.view(), true
+}
+
+func (a *Aggregator) onNode(obj interface{}) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.state.upsertNode(node)
+}
+
+func (a *Aggregator) onNodeDelete(obj interface{}) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.state.deleteNode(node.Name)
+}
+
+func (a *Aggregator) onPod(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.state.upsertPod(pod)
+}
+
+func (a *Aggregator) onPodDelete(obj interface{}) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.state.deletePod(pod.UID)
+}
+
+// buildState lists all nodes and pods and builds the totals from scratch
+func (c *Collector) buildState(ctx context.Context, reader client.Reader) (*clusterState, error) {
+	nodeList := &corev1.NodeList{}
+	if err := reader.List(ctx, nodeList); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	podList := &corev1.PodList{}
+	if err := reader.List(ctx, podList); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	state := newClusterState(c)
+	for i := range nodeList.Items {
+		state.upsertNode(&nodeList.Items[i])
+	}
+	for i := range podList.Items {
+		state.upsertPod(&podList.Items[i])
+	}
+	return state, nil
+}
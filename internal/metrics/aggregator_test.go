@@ -0,0 +1,221 @@
+package metrics
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// node builds a node with the given readiness and allocatable cpu and memory
+func node(name string, ready bool, cpu, memory string) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": name}},
+		Status: corev1.NodeStatus{
+			Capacity:    resources("cpu", cpu, "memory", memory, "pods", "110"),
+			Allocatable: resources("cpu", cpu, "memory", memory, "pods", "110"),
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+// pod builds a pod in the given phase, bound to nodeName unless empty
+func pod(name, nodeName string, phase corev1.PodPhase, priority int32, cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name)},
+		Spec: corev1.PodSpec{
+			NodeName:   nodeName,
+			Priority:   &priority,
+			Containers: []corev1.Container{container("app", cpu, memory)},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+// informerEvent is an event delivered by the node or pod informer
+type informerEvent struct {
+	name string
+	// object is added or updated, unless deleted is set
+	object  client.Object
+	deleted bool
+	// tombstone delivers the deletion as a DeletedFinalStateUnknown
+	tombstone bool
+}
+
+func TestAggregatorMatchesRebuild(t *testing.T) {
+	threshold := int32(100)
+
+	boundLater := pod("bound-later", "", corev1.PodPending, 1000, "1", "1Gi")
+	bound := boundLater.DeepCopy()
+	bound.Spec.NodeName = "node-b"
+	bound.Status.Phase = corev1.PodRunning
+
+	completing := pod("completing", "node-a", corev1.PodRunning, 1000, "2", "2Gi")
+	completed := completing.DeepCopy()
+	completed.Status.Phase = corev1.PodSucceeded
+
+	resized := pod("batch", "node-b", corev1.PodRunning, 0, "3", "1Gi")
+	resized.Spec.Containers[0].Resources.Requests = resources("cpu", "1", "memory", "3Gi")
+
+	events := []informerEvent{
+		{name: "add ready node", object: node("node-a", true, "8", "32Gi")},
+		{name: "add second ready node", object: node("node-b", true, "4", "16Gi")},
+		{name: "add running pod", object: pod("web", "node-a", corev1.PodRunning, 1000, "1", "2Gi")},
+		{name: "add completing pod", object: completing},
+		{name: "add unscheduled pod", object: boundLater},
+		{name: "bind unscheduled pod", object: bound},
+		{name: "pod reaches a terminal phase", object: completed},
+		{name: "add preemptible pod", object: pod("batch", "node-b", corev1.PodRunning, 0, "3", "1Gi")},
+		{name: "update preemptible pod requests", object: resized},
+		{name: "node becomes NotReady", object: node("node-b", false, "4", "16Gi")},
+		{name: "add pod on NotReady node", object: pod("late", "node-b", corev1.PodRunning, 1000, "500m", "512Mi")},
+		{name: "node becomes Ready again", object: node("node-b", true, "4", "16Gi")},
+		{name: "node allocatable changes", object: node("node-a", true, "6", "24Gi")},
+		{name: "add pod bound to an unknown node", object: pod("early", "node-c", corev1.PodRunning, 1000, "1", "1Gi")},
+		{name: "unknown node appears", object: node("node-c", true, "2", "4Gi")},
+		{name: "delete pod", object: pod("late", "", "", 0, "0", "0"), deleted: true},
+		{name: "delete completed pod", object: completed, deleted: true},
+		{name: "delete node with its pods still bound", object: node("node-a", true, "6", "24Gi"), deleted: true},
+		{name: "delete node through a tombstone", object: node("node-c", true, "2", "4Gi"), deleted: true, tombstone: true},
+		{name: "re-add a deleted node", object: node("node-a", true, "8", "32Gi")},
+		{name: "replay an event twice", object: node("node-a", true, "8", "32Gi")},
+	}
+
+	for _, accounting := range []PodAccountingPolicy{
+		{},
+		{IncludeUnscheduled: true, ReportPending: true},
+	} {
+		collector := &Collector{PodAccounting: accounting, PreemptiblePriorityThreshold: &threshold}
+		store := fake.NewClientBuilder().Build()
+		aggregator := &Aggregator{collector: collector, state: newClusterState(collector), synced: true}
+
+		for _, event := range events {
+			apply(t, store, event)
+			deliver(aggregator, event)
+
+			incremental, ok := aggregator.view()
+			if !ok {
+				t.Fatalf("aggregator is not synced")
+			}
+			rebuilt, err := collector.buildState(context.Background(), store)
+			if err != nil {
+				t.Fatalf("%s: buildState: %v", event.name, err)
+			}
+			compareSnapshots(t, accounting, event.name, incremental, rebuilt.view())
+		}
+	}
+}
+
+// apply stores the outcome of an event, as the API server would
+func apply(t *testing.T, store client.Client, event informerEvent) {
+	t.Helper()
+	ctx := context.Background()
+	object := event.object.DeepCopyObject().(client.Object)
+
+	if event.deleted {
+		if err := store.Delete(ctx, object); err != nil {
+			t.Fatalf("%s: delete: %v", event.name, err)
+		}
+		return
+	}
+
+	// The fake client leaves the status out of updates, so objects are replaced instead
+	if err := store.Delete(ctx, object.DeepCopyObject().(client.Object)); client.IgnoreNotFound(err) != nil {
+		t.Fatalf("%s: delete: %v", event.name, err)
+	}
+	if err := store.Create(ctx, object); err != nil {
+		t.Fatalf("%s: create: %v", event.name, err)
+	}
+}
+
+// deliver hands an event to the aggregator handlers, as the informers would
+func deliver(aggregator *Aggregator, event informerEvent) {
+	var object interface{} = event.object
+	if event.tombstone {
+		object = toolscache.DeletedFinalStateUnknown{Key: event.object.GetName(), Obj: event.object}
+	}
+
+	switch event.object.(type) {
+	case *corev1.Node:
+		if event.deleted {
+			aggregator.onNodeDelete(object)
+			return
+		}
+		aggregator.onNode(object)
+	case *corev1.Pod:
+		if event.deleted {
+			aggregator.onPodDelete(object)
+			return
+		}
+		aggregator.onPod(object)
+	}
+}
+
+// compareSnapshots reports every figure of got that differs from the rebuilt want
+func compareSnapshots(t *testing.T, accounting PodAccountingPolicy, event string, got, want *Snapshot) {
+	t.Helper()
+
+	lists := []struct {
+		name      string
+		got, want corev1.ResourceList
+	}{
+		{"capacity", got.cluster.capacity, want.cluster.capacity},
+		{"allocatable", got.cluster.allocatable, want.cluster.allocatable},
+		{"allocated", got.cluster.allocated, want.cluster.allocated},
+		{"preemptible", got.cluster.preemptible, want.cluster.preemptible},
+		{"borrowed", got.cluster.borrowed, want.cluster.borrowed},
+		{"pending", got.cluster.pending, want.cluster.pending},
+	}
+	for _, list := range lists {
+		if !equalResources(list.got, list.want) {
+			t.Errorf("%+v, after %s: %s = %v, rebuild gives %v", accounting, event, list.name, list.got, list.want)
+		}
+	}
+	if got.cluster.nodeCount != want.cluster.nodeCount {
+		t.Errorf("%+v, after %s: nodeCount = %d, rebuild gives %d", accounting, event, got.cluster.nodeCount, want.cluster.nodeCount)
+	}
+	if got.cluster.excludedNodes != want.cluster.excludedNodes {
+		t.Errorf("%+v, after %s: excludedNodes = %d, rebuild gives %d", accounting, event, got.cluster.excludedNodes, want.cluster.excludedNodes)
+	}
+
+	gotNodes, wantNodes := sortedNodes(got.nodes), sortedNodes(want.nodes)
+	if len(gotNodes) != len(wantNodes) {
+		t.Errorf("%+v, after %s: %d counted nodes, rebuild gives %d", accounting, event, len(gotNodes), len(wantNodes))
+		return
+	}
+	for i := range gotNodes {
+		g, w := gotNodes[i], wantNodes[i]
+		if g.name != w.name ||
+			!equalResources(g.capacity, w.capacity) ||
+			!equalResources(g.allocatable, w.allocatable) ||
+			!equalResources(g.allocated, w.allocated) {
+			t.Errorf("%+v, after %s: node %+v, rebuild gives %+v", accounting, event, g, w)
+		}
+	}
+}
+
+// equalResources compares two resource lists, an empty list being equal to a nil one
+func equalResources(a, b corev1.ResourceList) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return equality.Semantic.DeepEqual(a, b)
+}
+
+// sortedNodes orders node snapshots by name
+func sortedNodes(nodes []nodeSnapshot) []nodeSnapshot {
+	sorted := append([]nodeSnapshot(nil), nodes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	return sorted
+}
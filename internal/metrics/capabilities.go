@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// liqoControllerManager is the Deployment whose image tag gives the installed Liqo version
+const liqoControllerManager = "liqo-controller-manager"
+
+// softwareVersionsTTL is how long the detected software versions are reused before being read again
+const softwareVersionsTTL = 10 * time.Minute
+
+// softwareVersions are the versions of the software running the cluster, detected at observedAt
+type softwareVersions struct {
+	kubernetes string
+	cniPlugin  string
+	cniVersion string
+	liqo       string
+	observedAt time.Time
+}
+
+// gpuProductLabels are the node labels set by GPU feature discovery with the GPU model
+var gpuProductLabels = []string{
+	nvidiaProductLabel,
+	"amd.com/gpu.product-name",
+}
+
+// cniDaemonSets maps the DaemonSet names of well-known CNI plugins to the plugin name
+var cniDaemonSets = map[string]string{
+	"cilium":          "cilium",
+	"calico-node":     "calico",
+	"canal":           "canal",
+	"kube-flannel-ds": "flannel",
+	"kube-flannel":    "flannel",
+	"weave-net":       "weave",
+	"antrea-agent":    "antrea",
+	"kube-router":     "kube-router",
+	"aws-node":        "aws-vpc-cni",
+}
+
+// CollectCapabilities describes the advertised nodes and the software running the cluster.
+// Topology, platform and GPU product values are collected from the labels of the advertised nodes.
+func (c *Collector) CollectCapabilities(ctx context.Context, snapshot *Snapshot) (*rearv1alpha1.ClusterCapabilities, error) {
+	nodes := snapshot.nodes
+
+	regions := sets.New[string]()
+	zones := sets.New[string]()
+	architectures := sets.New[string]()
+	operatingSystems := sets.New[string]()
+	gpuProducts := sets.New[string]()
+
+	for _, node := range nodes {
+		insertLabel(regions, node.labels, corev1.LabelTopologyRegion)
+		insertLabel(zones, node.labels, corev1.LabelTopologyZone)
+		insertLabel(architectures, node.labels, corev1.LabelArchStable)
+		insertLabel(operatingSystems, node.labels, corev1.LabelOSStable)
+		for _, label := range gpuProductLabels {
+			insertLabel(gpuProducts, node.labels, label)
+		}
+	}
+
+	capabilities := &rearv1alpha1.ClusterCapabilities{
+		Regions:          sets.List(regions),
+		Zones:            sets.List(zones),
+		Architectures:    sets.List(architectures),
+		OperatingSystems: sets.List(operatingSystems),
+		GPUProducts:      sets.List(gpuProducts),
+	}
+
+	software, err := c.softwareVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	capabilities.KubernetesVersion = software.kubernetes
+	capabilities.CNIPlugin = software.cniPlugin
+	capabilities.CNIVersion = software.cniVersion
+	capabilities.LiqoVersion = software.liqo
+
+	return capabilities, nil
+}
+
+// softwareVersions returns the versions of the software running the cluster. They seldom
+// change, so they are detected again only once softwareVersionsTTL has passed.
+func (c *Collector) softwareVersions(ctx context.Context) (*softwareVersions, error) {
+	c.softwareMu.Lock()
+	defer c.softwareMu.Unlock()
+
+	if c.software != nil && time.Since(c.software.observedAt) < softwareVersionsTTL {
+		return c.software, nil
+	}
+
+	software := &softwareVersions{observedAt: time.Now()}
+	if c.ServerVersion != nil {
+		version, err := c.ServerVersion.ServerVersion()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Kubernetes version: %w", err)
+		}
+		software.kubernetes = version.GitVersion
+	}
+
+	var err error
+	if software.cniPlugin, software.cniVersion, err = c.detectCNI(ctx); err != nil {
+		return nil, err
+	}
+
+	if software.liqo, err = c.detectLiqoVersion(ctx); err != nil {
+		return nil, err
+	}
+
+	c.software = software
+	return software, nil
+}
+
+// uncachedReader returns the APIReader, falling back to the (cached) client
+func (c *Collector) uncachedReader() client.Reader {
+	if c.APIReader != nil {
+		return c.APIReader
+	}
+	return c.Client
+}
+
+// detectCNI looks for the DaemonSet of a well-known CNI plugin and returns its name and image version
+func (c *Collector) detectCNI(ctx context.Context) (string, string, error) {
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := c.uncachedReader().List(ctx, daemonSets); err != nil {
+		return "", "", fmt.Errorf("failed to list daemon sets: %w", err)
+	}
+
+	// Sorting keeps the result stable when several plugins are installed (e.g. chained CNIs)
+	sort.Slice(daemonSets.Items, func(i, j int) bool {
+		return daemonSets.Items[i].Name < daemonSets.Items[j].Name
+	})
+
+	for _, daemonSet := range daemonSets.Items {
+		plugin, ok := cniDaemonSets[daemonSet.Name]
+		if !ok {
+			continue
+		}
+		return plugin, podTemplateVersion(&daemonSet.Spec.Template), nil
+	}
+	return "", "", nil
+}
+
+// detectLiqoVersion returns the image version of the Liqo controller manager, or "" if Liqo is not installed
+func (c *Collector) detectLiqoVersion(ctx context.Context) (string, error) {
+	if c.LiqoNamespace == "" {
+		return "", nil
+	}
+
+	deployment := &appsv1.Deployment{}
+	key := client.ObjectKey{Namespace: c.LiqoNamespace, Name: liqoControllerManager}
+	if err := c.uncachedReader().Get(ctx, key, deployment); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get Liqo controller manager: %w", err)
+	}
+	return podTemplateVersion(&deployment.Spec.Template), nil
+}
+
+// podTemplateVersion returns the image tag of the first container of a pod template
+func podTemplateVersion(template *corev1.PodTemplateSpec) string {
+	if len(template.Spec.Containers) == 0 {
+		return ""
+	}
+
+	image := template.Spec.Containers[0].Image
+	image, _, _ = strings.Cut(image, "@")
+	// The tag follows the last colon, unless that colon belongs to a registry port
+	if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i:], "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// insertLabel adds the value of a node label to values when it is set
+func insertLabel(values sets.Set[string], labels map[string]string, label string) {
+	if value := labels[label]; value != "" {
+		values.Insert(value)
+	}
+}
@@ -3,226 +3,228 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/discovery"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+	"github.com/mehdiazizian/liqo-resource-agent/internal/policy"
 )
 
+// gpuResourceName is the extended resource reported in the dedicated GPU field
+const gpuResourceName corev1.ResourceName = "nvidia.com/gpu"
+
 // Collector collects resource metrics from the cluster
 type Collector struct {
 	Client            client.Client
 	ClusterIDOverride string
-}
 
-// CollectClusterResources collects detailed resource information from all nodes
-func (c *Collector) CollectClusterResources(ctx context.Context) (*rearv1alpha1.ResourceMetrics, error) {
-	nodeList := &corev1.NodeList{}
-	if err := c.Client.List(ctx, nodeList); err != nil {
-		return nil, fmt.Errorf("failed to list nodes: %w", err)
-	}
+	// ExtendedResources lists the extended resources to aggregate besides CPU, memory
+	// and nvidia.com/gpu. Entries are exact resource names (e.g. "amd.com/gpu") or
+	// prefix patterns ending with "*" (e.g. "hugepages-*", "xilinx.com/*").
+	ExtendedResources []string
 
-	if len(nodeList.Items) == 0 {
-		return nil, fmt.Errorf("no nodes found in cluster")
-	}
+	// UsageSource optionally reports actual consumption (e.g. metrics-server)
+	UsageSource UsageSource
 
-	// Initialize totals
-	capacity := &rearv1alpha1.ResourceQuantities{
-		CPU:    *resource.NewQuantity(0, resource.DecimalSI),
-		Memory: *resource.NewQuantity(0, resource.BinarySI),
-	}
-	allocatable := &rearv1alpha1.ResourceQuantities{
-		CPU:    *resource.NewQuantity(0, resource.DecimalSI),
-		Memory: *resource.NewQuantity(0, resource.BinarySI),
-	}
+	// DeviceSource optionally reports Dynamic Resource Allocation devices
+	DeviceSource DeviceSource
 
-	var capacityGPU, allocatableGPU resource.Quantity
-	hasGPU := false
+	// Aggregator optionally provides incrementally maintained node and pod totals
+	Aggregator *Aggregator
 
-	// Aggregate capacity and allocatable from all ready nodes
-	for _, node := range nodeList.Items {
-		if !isNodeReady(&node) {
-			continue
-		}
+	// NodePoolLabel is the node label used to group nodes into pools (empty disables the breakdown)
+	NodePoolLabel string
 
-		// Capacity
-		if cpu, ok := node.Status.Capacity[corev1.ResourceCPU]; ok {
-			capacity.CPU.Add(cpu)
-		}
-		if memory, ok := node.Status.Capacity[corev1.ResourceMemory]; ok {
-			capacity.Memory.Add(memory)
-		}
-		if gpu, ok := node.Status.Capacity["nvidia.com/gpu"]; ok {
-			capacityGPU.Add(gpu)
-			hasGPU = true
-		}
+	// NodeSelector optionally restricts which ready nodes are advertised
+	NodeSelector *NodeSelector
 
-		// Allocatable
-		if cpu, ok := node.Status.Allocatable[corev1.ResourceCPU]; ok {
-			allocatable.CPU.Add(cpu)
-		}
-		if memory, ok := node.Status.Allocatable[corev1.ResourceMemory]; ok {
-			allocatable.Memory.Add(memory)
-		}
-		if gpu, ok := node.Status.Allocatable["nvidia.com/gpu"]; ok {
-			allocatableGPU.Add(gpu)
-		}
-	}
+	// PodAccounting decides which pods count toward the allocated totals
+	PodAccounting PodAccountingPolicy
+
+	// AdvertiseStorage enables persistent storage accounting (CSIStorageCapacity and
+	// bound PVCs); the reported Storage then also covers node ephemeral storage
+	AdvertiseStorage bool
+
+	// PreemptiblePriorityThreshold, when set, reports the resources held by pods with a
+	// lower priority as preemptible capacity
+	PreemptiblePriorityThreshold *int32
+
+	// OffloadNamespaces optionally lists the namespaces remote workloads land in;
+	// advertised availability is then capped by the ResourceQuota left in them
+	OffloadNamespaces []string
+
+	// ServerVersion optionally reports the Kubernetes version advertised in the capabilities
+	ServerVersion discovery.ServerVersionInterface
+
+	// LiqoNamespace is where Liqo is installed (empty skips Liqo version detection)
+	LiqoNamespace string
 
-	if hasGPU {
-		capacity.GPU = &capacityGPU
-		allocatable.GPU = &allocatableGPU
+	// APIReader optionally reads objects bypassing the cache, for reads too infrequent
+	// to justify a cluster-wide informer (e.g. the Liqo and CNI workloads)
+	APIReader client.Reader
+
+	softwareMu sync.Mutex
+	software   *softwareVersions
+}
+
+// CollectClusterResources collects detailed resource information from the nodes of the snapshot.
+// Liqo virtual nodes and the pods offloaded to them are left out of every figure.
+// The lending rules of lendingPolicy and of the schedule state, when not nil, and the
+// quota left in the offload namespaces are applied to Available; the returned constraints
+// tell, per resource, which of them was binding.
+func (c *Collector) CollectClusterResources(
+	ctx context.Context,
+	snapshot *Snapshot,
+	lendingPolicy *rearv1alpha1.AdvertisementPolicySpec,
+	schedule *policy.ScheduleState,
+) (*rearv1alpha1.ResourceMetrics, []rearv1alpha1.AvailabilityConstraint, error) {
+	totals := snapshot.cluster
+	if totals.nodeCount == 0 {
+		return nil, nil, fmt.Errorf("no nodes found in cluster")
 	}
 
-	// Calculate allocated resources from all pods
-	allocated, err := c.calculateAllocatedResources(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to calculate allocated resources: %w", err)
+	// The snapshot is shared with the other collectors, so its totals are not modified
+	capacity := totals.capacity.DeepCopy()
+	allocatable := totals.allocatable.DeepCopy()
+	allocated := totals.allocated.DeepCopy()
+
+	if c.AdvertiseStorage {
+		if err := c.addPersistentStorage(ctx, capacity, allocatable, allocated); err != nil {
+			return nil, nil, fmt.Errorf("failed to collect storage: %w", err)
+		}
 	}
 
 	// Calculate reserved resources from provider instructions
 	reserved, err := c.calculateReservedResources(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate reserved resources: %w", err)
+		return nil, nil, fmt.Errorf("failed to calculate reserved resources: %w", err)
 	}
 
-	// Calculate available = allocatable - allocated - reserved
-	available := &rearv1alpha1.ResourceQuantities{
-		CPU:    allocatable.CPU.DeepCopy(),
-		Memory: allocatable.Memory.DeepCopy(),
-	}
-	available.CPU.Sub(allocated.CPU)
-	available.Memory.Sub(allocated.Memory)
-	available.CPU.Sub(reserved.CPU)
-	available.Memory.Sub(reserved.Memory)
-
-	if hasGPU && allocatable.GPU != nil {
-		availableGPU := allocatable.GPU.DeepCopy()
-		if allocated.GPU != nil {
-			availableGPU.Sub(*allocated.GPU)
+	// Every resource the cluster offers is reported as allocated, even when no pod requests it
+	for name := range allocatable {
+		if _, ok := allocated[name]; !ok {
+			allocated[name] = *resource.NewQuantity(0, allocatable[name].Format)
 		}
-		if reserved.GPU != nil {
-			availableGPU.Sub(*reserved.GPU)
-		}
-		available.GPU = &availableGPU
 	}
 
-	return &rearv1alpha1.ResourceMetrics{
-		Capacity:    *capacity,
-		Allocatable: *allocatable,
-		Allocated:   *allocated,
-		Available:   *available,
-		// Used: nil, // Can be populated with metrics-server data if available
-	}, nil
-}
-
-// calculateAllocatedResources sums up all resource requests from running pods
-func (c *Collector) calculateAllocatedResources(ctx context.Context) (*rearv1alpha1.ResourceQuantities, error) {
-	podList := &corev1.PodList{}
-	if err := c.Client.List(ctx, podList); err != nil {
-		return nil, fmt.Errorf("failed to list pods: %w", err)
+	// Calculate available = allocatable - allocated - reserved
+	available := allocatable.DeepCopy()
+	subtractResources(available, allocated)
+	subtractResources(available, reserved)
+	constraints := map[corev1.ResourceName]string{}
+	for name := range available {
+		constraints[name] = rearv1alpha1.ConstraintFreeCapacity
 	}
 
-	allocated := &rearv1alpha1.ResourceQuantities{
-		CPU:    *resource.NewQuantity(0, resource.DecimalSI),
-		Memory: *resource.NewQuantity(0, resource.BinarySI),
+	// Keep the configured buffer for local workloads out of what is advertised
+	free := available.DeepCopy()
+	if err := policy.Apply(lendingPolicy, allocatable, reserved, available); err != nil {
+		return nil, nil, fmt.Errorf("failed to apply advertisement policy: %w", err)
+	}
+	for name, quantity := range available {
+		if quantity.Cmp(free[name]) != 0 {
+			constraints[name] = rearv1alpha1.ConstraintAdvertisementPolicy
+		}
 	}
 
-	var allocatedGPU resource.Quantity
-	hasGPU := false
+	// Outside lending windows, lend less or nothing at all
+	lendable := available.DeepCopy()
+	if err := policy.ApplySchedule(schedule, allocatable, reserved, available); err != nil {
+		return nil, nil, fmt.Errorf("failed to apply lending schedule: %w", err)
+	}
+	for name, quantity := range available {
+		if quantity.Cmp(lendable[name]) != 0 {
+			constraints[name] = rearv1alpha1.ConstraintLendingSchedule
+		}
+	}
 
-	for _, pod := range podList.Items {
-		if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
-			continue
+	// Remote workloads cannot use more than the quota left where they land
+	quota, err := c.quotaRemaining(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read offload namespace quotas: %w", err)
+	}
+	for name, left := range quota {
+		if quantity, ok := available[name]; ok && left.Cmp(quantity) < 0 {
+			available[name] = left
+			constraints[name] = rearv1alpha1.ConstraintResourceQuota
 		}
+	}
 
-		containersCPU := resource.NewQuantity(0, resource.DecimalSI)
-		containersMemory := resource.NewQuantity(0, resource.BinarySI)
-		containersGPU := resource.NewQuantity(0, resource.DecimalSI)
+	metrics := &rearv1alpha1.ResourceMetrics{
+		Capacity:    c.toResourceQuantities(capacity),
+		Allocatable: c.toResourceQuantities(allocatable),
+		Allocated:   c.toResourceQuantities(allocated),
+		Available:   c.toResourceQuantities(available),
+		// Used is populated separately by CollectUsage when a UsageSource is configured
+	}
 
-		initCPUMax := resource.NewQuantity(0, resource.DecimalSI)
-		initMemoryMax := resource.NewQuantity(0, resource.BinarySI)
-		initGPUMax := resource.NewQuantity(0, resource.DecimalSI)
+	// Capacity of Liqo virtual nodes is reported apart, never as our own
+	if len(totals.borrowed) > 0 {
+		borrowed := c.toResourceQuantities(totals.borrowed)
+		metrics.Borrowed = &borrowed
+	}
 
-		for _, container := range pod.Spec.Containers {
-			if cpu, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
-				containersCPU.Add(cpu)
-			}
-			if memory, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
-				containersMemory.Add(memory)
-			}
-			if gpu, ok := container.Resources.Requests["nvidia.com/gpu"]; ok {
-				containersGPU.Add(gpu)
-				hasGPU = true
-			}
-		}
+	// Capacity held by low-priority pods could be reclaimed for a lower-guarantee tier
+	if c.PreemptiblePriorityThreshold != nil {
+		preemptible := c.toResourceQuantities(totals.preemptible)
+		metrics.Preemptible = &preemptible
+	}
 
-		for _, initContainer := range pod.Spec.InitContainers {
-			if cpu, ok := initContainer.Resources.Requests[corev1.ResourceCPU]; ok {
-				if cpu.Cmp(*initCPUMax) > 0 {
-					*initCPUMax = cpu.DeepCopy()
-				}
-			}
-			if memory, ok := initContainer.Resources.Requests[corev1.ResourceMemory]; ok {
-				if memory.Cmp(*initMemoryMax) > 0 {
-					*initMemoryMax = memory.DeepCopy()
-				}
-			}
-			if gpu, ok := initContainer.Resources.Requests["nvidia.com/gpu"]; ok {
-				if gpu.Cmp(*initGPUMax) > 0 {
-					*initGPUMax = gpu.DeepCopy()
-				}
-				hasGPU = true
-			}
-		}
+	// Demand of pods waiting for a node is reported apart from what is allocated
+	if c.PodAccounting.ReportPending {
+		pending := c.toResourceQuantities(totals.pending)
+		metrics.Pending = &pending
+	}
 
-		cpuContribution := containersCPU.DeepCopy()
-		if initCPUMax.Cmp(cpuContribution) > 0 {
-			cpuContribution = initCPUMax.DeepCopy()
-		}
-		allocated.CPU.Add(cpuContribution)
+	return metrics, toAvailabilityConstraints(constraints), nil
+}
 
-		memoryContribution := containersMemory.DeepCopy()
-		if initMemoryMax.Cmp(memoryContribution) > 0 {
-			memoryContribution = initMemoryMax.DeepCopy()
-		}
-		allocated.Memory.Add(memoryContribution)
+// toAvailabilityConstraints converts the binding constraint of each resource to the API representation
+func toAvailabilityConstraints(constraints map[corev1.ResourceName]string) []rearv1alpha1.AvailabilityConstraint {
+	result := make([]rearv1alpha1.AvailabilityConstraint, 0, len(constraints))
+	for name, constraint := range constraints {
+		result = append(result, rearv1alpha1.AvailabilityConstraint{
+			Resource:   string(name),
+			Constraint: constraint,
+		})
+	}
 
-		if hasGPU {
-			gpuContribution := containersGPU.DeepCopy()
-			if initGPUMax.Cmp(gpuContribution) > 0 {
-				gpuContribution = initGPUMax.DeepCopy()
-			}
-			allocatedGPU.Add(gpuContribution)
-		}
+	sort.Slice(result, func(i, j int) bool { return result[i].Resource < result[j].Resource })
 
-		if pod.Spec.Overhead != nil {
-			if cpu, ok := pod.Spec.Overhead[corev1.ResourceCPU]; ok {
-				allocated.CPU.Add(cpu)
-			}
-			if memory, ok := pod.Spec.Overhead[corev1.ResourceMemory]; ok {
-				allocated.Memory.Add(memory)
-			}
-			if gpu, ok := pod.Spec.Overhead["nvidia.com/gpu"]; ok {
-				allocatedGPU.Add(gpu)
-				hasGPU = true
-			}
+	return result
+}
+
+// Snapshot takes the node and pod totals from the Aggregator, falling back to a full List.
+// The snapshot is shared by the collectors of a reconcile.
+func (c *Collector) Snapshot(ctx context.Context) (*Snapshot, error) {
+	if c.Aggregator != nil {
+		if snapshot, ok := c.Aggregator.view(); ok {
+			return snapshot, nil
 		}
 	}
 
-	if hasGPU {
-		allocated.GPU = &allocatedGPU
+	state, err := c.buildState(ctx, c.Client)
+	if err != nil {
+		return nil, err
 	}
+	return state.view(), nil
+}
 
-	return allocated, nil
+// ExcludedNodeCount returns the number of ready nodes left out by the NodeSelector
+func (c *Collector) ExcludedNodeCount(snapshot *Snapshot) int32 {
+	return int32(snapshot.cluster.excludedNodes)
 }
 
 // calculateReservedResources sums up resources reserved by provider instructions
-func (c *Collector) calculateReservedResources(ctx context.Context) (*rearv1alpha1.ResourceQuantities, error) {
+func (c *Collector) calculateReservedResources(ctx context.Context) (corev1.ResourceList, error) {
 	logger := log.FromContext(ctx).WithName("metrics-collector")
 
 	providerInstructionList := &rearv1alpha1.ProviderInstructionList{}
@@ -230,13 +232,11 @@ func (c *Collector) calculateReservedResources(ctx context.Context) (*rearv1alph
 		return nil, fmt.Errorf("failed to list provider instructions: %w", err)
 	}
 
-	reserved := &rearv1alpha1.ResourceQuantities{
-		CPU:    *resource.NewQuantity(0, resource.DecimalSI),
-		Memory: *resource.NewQuantity(0, resource.BinarySI),
+	reserved := corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewQuantity(0, resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(0, resource.BinarySI),
 	}
 
-	var reservedGPU resource.Quantity
-	hasGPU := false
 	now := time.Now()
 
 	for _, instruction := range providerInstructionList.Items {
@@ -259,7 +259,7 @@ func (c *Collector) calculateReservedResources(ctx context.Context) (*rearv1alph
 					"cpu", instruction.Spec.RequestedCPU)
 				continue
 			}
-			reserved.CPU.Add(cpuQuantity)
+			c.addResources(reserved, corev1.ResourceList{corev1.ResourceCPU: cpuQuantity})
 		}
 
 		// Parse Memory
@@ -271,27 +271,147 @@ func (c *Collector) calculateReservedResources(ctx context.Context) (*rearv1alph
 					"memory", instruction.Spec.RequestedMemory)
 				continue
 			}
-			reserved.Memory.Add(memQuantity)
+			c.addResources(reserved, corev1.ResourceList{corev1.ResourceMemory: memQuantity})
 		}
 
-		// GPU support (if added later to ProviderInstruction)
-		// For now, skip GPU handling in provider instructions
-	}
+		// Parse Storage
+		if instruction.Spec.RequestedStorage != "" {
+			storageQuantity, err := resource.ParseQuantity(instruction.Spec.RequestedStorage)
+			if err != nil {
+				logger.Error(err, "failed to parse storage from provider instruction",
+					"instruction", instruction.Name,
+					"storage", instruction.Spec.RequestedStorage)
+				continue
+			}
+			c.addResources(reserved, corev1.ResourceList{corev1.ResourceStorage: storageQuantity})
+		}
 
-	if hasGPU {
-		reserved.GPU = &reservedGPU
+		// GPU and extended resources (if added later to ProviderInstruction)
+		// For now, skip them in provider instructions
 	}
 
-	if reserved.CPU.Sign() > 0 || reserved.Memory.Sign() > 0 {
+	reservedCPU := reserved[corev1.ResourceCPU]
+	reservedMemory := reserved[corev1.ResourceMemory]
+	if reservedCPU.Sign() > 0 || reservedMemory.Sign() > 0 {
 		logger.Info("calculated reserved resources from provider instructions",
-			"reservedCPU", reserved.CPU.String(),
-			"reservedMemory", reserved.Memory.String(),
+			"reservedCPU", reservedCPU.String(),
+			"reservedMemory", reservedMemory.String(),
 			"instructionCount", len(providerInstructionList.Items))
 	}
 
 	return reserved, nil
 }
 
+// isTracked reports whether a resource is aggregated by the collector
+func (c *Collector) isTracked(name corev1.ResourceName) bool {
+	switch name {
+	case corev1.ResourceCPU, corev1.ResourceMemory, gpuResourceName,
+		corev1.ResourceEphemeralStorage, corev1.ResourcePods:
+		return true
+	case corev1.ResourceStorage:
+		return c.AdvertiseStorage
+	}
+	return isMIGResource(name) || c.isExtendedResource(name)
+}
+
+// isExtendedResource reports whether a resource matches one of the configured extended resources
+func (c *Collector) isExtendedResource(name corev1.ResourceName) bool {
+	for _, pattern := range c.ExtendedResources {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(string(name), prefix) {
+				return true
+			}
+			continue
+		}
+		if string(name) == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// addResources adds every tracked resource of src to dst
+func (c *Collector) addResources(dst, src corev1.ResourceList) {
+	for name, quantity := range src {
+		if !c.isTracked(name) {
+			continue
+		}
+		total := dst[name]
+		total.Add(quantity)
+		dst[name] = total
+	}
+}
+
+// maxResources raises every tracked resource of dst to at least its value in src
+func (c *Collector) maxResources(dst, src corev1.ResourceList) {
+	for name, quantity := range src {
+		if !c.isTracked(name) {
+			continue
+		}
+		if current, ok := dst[name]; !ok || quantity.Cmp(current) > 0 {
+			dst[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+// subtractResources subtracts from dst the resources of src that dst already tracks
+func subtractResources(dst, src corev1.ResourceList) {
+	for name, quantity := range src {
+		total, ok := dst[name]
+		if !ok {
+			continue
+		}
+		total.Sub(quantity)
+		dst[name] = total
+	}
+}
+
+// toResourceQuantities converts an aggregated resource list to the API representation
+func (c *Collector) toResourceQuantities(rl corev1.ResourceList) rearv1alpha1.ResourceQuantities {
+	rq := rearv1alpha1.ResourceQuantities{
+		CPU:    *resource.NewQuantity(0, resource.DecimalSI),
+		Memory: *resource.NewQuantity(0, resource.BinarySI),
+	}
+
+	for name, quantity := range rl {
+		switch {
+		case name == corev1.ResourceCPU:
+			rq.CPU.Add(quantity)
+		case name == corev1.ResourceMemory:
+			rq.Memory.Add(quantity)
+		case name == gpuResourceName:
+			gpu := quantity.DeepCopy()
+			rq.GPU = &gpu
+		case name == corev1.ResourcePods:
+			pods := quantity.DeepCopy()
+			rq.Pods = &pods
+		case name == corev1.ResourceEphemeralStorage:
+			ephemeral := quantity.DeepCopy()
+			rq.EphemeralStorage = &ephemeral
+			// Storage also covers node ephemeral storage when storage is advertised
+			if c.AdvertiseStorage {
+				if rq.Storage == nil {
+					rq.Storage = resource.NewQuantity(0, resource.BinarySI)
+				}
+				rq.Storage.Add(quantity)
+			}
+		case name == corev1.ResourceStorage:
+			if rq.Storage == nil {
+				rq.Storage = resource.NewQuantity(0, resource.BinarySI)
+			}
+			rq.Storage.Add(quantity)
+		// MIG devices are always advertised, so that the GPU breakdown can be bounded by them
+		case isMIGResource(name) || c.isExtendedResource(name):
+			if rq.Extended == nil {
+				rq.Extended = map[string]resource.Quantity{}
+			}
+			rq.Extended[string(name)] = quantity.DeepCopy()
+		}
+	}
+
+	return rq
+}
+
 // isNodeReady checks if a node is in Ready condition
 func isNodeReady(node *corev1.Node) bool {
 	for _, condition := range node.Status.Conditions {
@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	resourcev1 "k8s.io/api/resource/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// ErrDRAUnavailable is returned when the cluster does not serve the resource.k8s.io/v1 API
+var ErrDRAUnavailable = errors.New("resource.k8s.io/v1 API is not available")
+
+// driverSelector matches the CEL selectors restricting a DeviceClass to a single driver,
+// which is how DRA drivers define their classes (e.g. device.driver == "gpu.nvidia.com")
+var driverSelector = regexp.MustCompile(`^\s*device\.driver\s*==\s*["']([^"']+)["']\s*$`)
+
+// DeviceSource reports the DRA devices offered by the advertised nodes
+type DeviceSource interface {
+	// DeviceClasses counts, per DeviceClass, the devices available to the given nodes
+	DeviceClasses(ctx context.Context, nodes sets.Set[string]) ([]rearv1alpha1.DeviceClassResources, error)
+}
+
+// DRADeviceSource counts devices from ResourceSlices and allocated ResourceClaims.
+// CEL is not evaluated: only DeviceClasses without selectors or selecting a single
+// driver are reported.
+type DRADeviceSource struct {
+	Reader client.Reader
+}
+
+// deviceID identifies a device published in a ResourceSlice
+type deviceID struct {
+	driver string
+	pool   string
+	device string
+}
+
+// DeviceClasses lists DeviceClasses, ResourceSlices and ResourceClaims and counts the devices of each class
+func (s *DRADeviceSource) DeviceClasses(
+	ctx context.Context,
+	nodes sets.Set[string],
+) ([]rearv1alpha1.DeviceClassResources, error) {
+	logger := log.FromContext(ctx).WithName("metrics-collector")
+
+	classes := &resourcev1.DeviceClassList{}
+	if err := s.Reader.List(ctx, classes); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("%w: %v", ErrDRAUnavailable, err)
+		}
+		return nil, fmt.Errorf("failed to list device classes: %w", err)
+	}
+
+	slices := &resourcev1.ResourceSliceList{}
+	if err := s.Reader.List(ctx, slices); err != nil {
+		return nil, fmt.Errorf("failed to list resource slices: %w", err)
+	}
+
+	claims := &resourcev1.ResourceClaimList{}
+	if err := s.Reader.List(ctx, claims); err != nil {
+		return nil, fmt.Errorf("failed to list resource claims: %w", err)
+	}
+
+	// Slices of an outdated pool generation are being replaced and must be ignored
+	generations := map[[2]string]int64{}
+	for _, slice := range slices.Items {
+		key := [2]string{slice.Spec.Driver, slice.Spec.Pool.Name}
+		if slice.Spec.Pool.Generation > generations[key] {
+			generations[key] = slice.Spec.Pool.Generation
+		}
+	}
+
+	devices := sets.New[deviceID]()
+	for _, slice := range slices.Items {
+		if slice.Spec.Pool.Generation < generations[[2]string{slice.Spec.Driver, slice.Spec.Pool.Name}] {
+			continue
+		}
+		// Node-local devices only count on advertised nodes; network-attached ones always do
+		if slice.Spec.NodeName != nil && !nodes.Has(*slice.Spec.NodeName) {
+			continue
+		}
+		for _, device := range slice.Spec.Devices {
+			devices.Insert(deviceID{driver: slice.Spec.Driver, pool: slice.Spec.Pool.Name, device: device.Name})
+		}
+	}
+
+	allocated := sets.New[deviceID]()
+	for _, claim := range claims.Items {
+		if claim.Status.Allocation == nil {
+			continue
+		}
+		for _, result := range claim.Status.Allocation.Devices.Results {
+			allocated.Insert(deviceID{driver: result.Driver, pool: result.Pool, device: result.Device})
+		}
+	}
+
+	result := make([]rearv1alpha1.DeviceClassResources, 0, len(classes.Items))
+	for _, class := range classes.Items {
+		driver, ok := classDriver(&class)
+		if !ok {
+			logger.V(1).Info("skipping device class with unsupported selectors", "deviceClass", class.Name)
+			continue
+		}
+
+		resources := rearv1alpha1.DeviceClassResources{DeviceClass: class.Name, Driver: driver}
+		for id := range devices {
+			if driver != "" && id.driver != driver {
+				continue
+			}
+			resources.Capacity++
+			if allocated.Has(id) {
+				resources.Allocated++
+			}
+		}
+		if resources.Capacity == 0 {
+			continue
+		}
+		resources.Available = resources.Capacity - resources.Allocated
+		result = append(result, resources)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].DeviceClass < result[j].DeviceClass })
+
+	return result, nil
+}
+
+// classDriver returns the driver a DeviceClass is restricted to ("" for every driver),
+// or false when its selectors go beyond a driver equality
+func classDriver(class *resourcev1.DeviceClass) (string, bool) {
+	driver := ""
+	for _, selector := range class.Spec.Selectors {
+		if selector.CEL == nil {
+			return "", false
+		}
+		match := driverSelector.FindStringSubmatch(selector.CEL.Expression)
+		if match == nil || (driver != "" && driver != match[1]) {
+			return "", false
+		}
+		driver = match[1]
+	}
+	return driver, true
+}
+
+// CollectDevices counts the DRA devices of each DeviceClass on the advertised nodes.
+// It returns nil without error when no DeviceSource is configured.
+func (c *Collector) CollectDevices(ctx context.Context, snapshot *Snapshot) ([]rearv1alpha1.DeviceClassResources, error) {
+	if c.DeviceSource == nil {
+		return nil, nil
+	}
+
+	names := sets.New[string]()
+	for _, node := range snapshot.nodes {
+		names.Insert(node.name)
+	}
+
+	return c.DeviceSource.DeviceClasses(ctx, names)
+}
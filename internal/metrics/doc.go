@@ -2,4 +2,7 @@
 // from a Kubernetes cluster. It gathers information about node capacity,
 // allocatable resources, and pod resource requests to calculate the
 // overall cluster resource availability.
+//
+// Every list a collector returns is sorted, since it is built from maps: the
+// output sent to the broker is then deterministic and easy to diff.
 package metrics
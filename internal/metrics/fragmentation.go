@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// Histogram bucket lower bounds; each bucket ends where the next one starts
+var (
+	cpuChunkBounds    = []string{"0", "1", "2", "4", "8", "16", "32"}
+	memoryChunkBounds = []string{"0", "1Gi", "2Gi", "4Gi", "8Gi", "16Gi", "32Gi", "64Gi", "128Gi"}
+)
+
+// CollectFragmentation computes how the free resources are spread across nodes.
+// LargestSchedulable holds, for each resource, the largest amount free on a single
+// node, i.e. the biggest request of that resource a pod could make and still fit.
+// Like the node pool breakdown, it does not account for cluster-wide reservations.
+func (c *Collector) CollectFragmentation(snapshot *Snapshot) *rearv1alpha1.FragmentationInfo {
+	nodes := snapshot.nodes
+
+	largest := corev1.ResourceList{}
+	freeCPU := make([]resource.Quantity, 0, len(nodes))
+	freeMemory := make([]resource.Quantity, 0, len(nodes))
+
+	for _, node := range nodes {
+		free := node.allocatable.DeepCopy()
+		subtractResources(free, node.allocated)
+		for name, quantity := range free {
+			if quantity.Sign() < 0 {
+				free[name] = *resource.NewQuantity(0, quantity.Format)
+			}
+		}
+
+		c.maxResources(largest, free)
+		freeCPU = append(freeCPU, free[corev1.ResourceCPU])
+		freeMemory = append(freeMemory, free[corev1.ResourceMemory])
+	}
+
+	return &rearv1alpha1.FragmentationInfo{
+		LargestSchedulable: c.toResourceQuantities(largest),
+		CPUChunks:          chunkHistogram(freeCPU, cpuChunkBounds),
+		MemoryChunks:       chunkHistogram(freeMemory, memoryChunkBounds),
+	}
+}
+
+// chunkHistogram counts the values falling in each bucket delimited by bounds
+func chunkHistogram(values []resource.Quantity, bounds []string) []rearv1alpha1.FreeChunkBucket {
+	buckets := make([]rearv1alpha1.FreeChunkBucket, len(bounds))
+	for i, bound := range bounds {
+		buckets[i].LowerBound = resource.MustParse(bound)
+		if i+1 < len(bounds) {
+			upper := resource.MustParse(bounds[i+1])
+			buckets[i].UpperBound = &upper
+		}
+	}
+
+	for _, value := range values {
+		// Walk down from the largest bucket to find the first lower bound <= value
+		for i := len(buckets) - 1; i >= 0; i-- {
+			if value.Cmp(buckets[i].LowerBound) >= 0 {
+				buckets[i].Nodes++
+				break
+			}
+		}
+	}
+
+	return buckets
+}
@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// Node labels set by NVIDIA GPU feature discovery
+const (
+	nvidiaProductLabel     = "nvidia.com/gpu.product"
+	nvidiaMemoryLabel      = "nvidia.com/gpu.memory" // MiB per GPU
+	nvidiaMIGStrategyLabel = "nvidia.com/mig.strategy"
+)
+
+// migResourcePrefix marks the extended resources exposing MIG devices under the mixed strategy
+const migResourcePrefix = "nvidia.com/mig-"
+
+// unknownGPUProduct groups GPU nodes not labeled by GPU feature discovery
+const unknownGPUProduct = "unknown"
+
+// isMIGResource reports whether a resource is a MIG device profile (e.g. nvidia.com/mig-1g.5gb)
+func isMIGResource(name corev1.ResourceName) bool {
+	return strings.HasPrefix(string(name), migResourcePrefix)
+}
+
+// CollectGPUs aggregates the GPUs of the advertised nodes per product and MIG strategy,
+// with the MIG profiles they expose. It returns nil when no advertised node has GPUs.
+func (c *Collector) CollectGPUs(snapshot *Snapshot) []rearv1alpha1.GPUModelResources {
+	nodes := snapshot.nodes
+
+	type modelKey struct {
+		product     string
+		migStrategy string
+	}
+	type modelTotals struct {
+		nodeCount   int32
+		memory      *resource.Quantity
+		allocatable corev1.ResourceList
+		allocated   corev1.ResourceList
+	}
+
+	models := map[modelKey]*modelTotals{}
+	for _, node := range nodes {
+		gpus := corev1.ResourceList{}
+		for name, quantity := range node.allocatable {
+			if name == gpuResourceName || isMIGResource(name) {
+				gpus[name] = quantity
+			}
+		}
+		if len(gpus) == 0 {
+			continue
+		}
+
+		key := modelKey{product: node.labels[nvidiaProductLabel], migStrategy: node.labels[nvidiaMIGStrategyLabel]}
+		if key.product == "" {
+			key.product = unknownGPUProduct
+		}
+
+		model, ok := models[key]
+		if !ok {
+			model = &modelTotals{
+				allocatable: corev1.ResourceList{},
+				allocated:   corev1.ResourceList{},
+			}
+			models[key] = model
+		}
+
+		model.nodeCount++
+		if model.memory == nil {
+			if mib, err := strconv.ParseInt(node.labels[nvidiaMemoryLabel], 10, 64); err == nil {
+				model.memory = resource.NewQuantity(mib*1024*1024, resource.BinarySI)
+			}
+		}
+		c.addResources(model.allocatable, gpus)
+		for name := range gpus {
+			if quantity, ok := node.allocated[name]; ok {
+				c.addResources(model.allocated, corev1.ResourceList{name: quantity})
+			}
+		}
+	}
+
+	if len(models) == 0 {
+		return nil
+	}
+
+	result := make([]rearv1alpha1.GPUModelResources, 0, len(models))
+	for key, model := range models {
+		gpuModel := rearv1alpha1.GPUModelResources{
+			Product:     key.product,
+			MIGStrategy: key.migStrategy,
+			Memory:      model.memory,
+			NodeCount:   model.nodeCount,
+		}
+		gpuModel.Allocatable, gpuModel.Allocated, gpuModel.Available = gpuFigures(model.allocatable, model.allocated, gpuResourceName)
+
+		for name := range model.allocatable {
+			if !isMIGResource(name) {
+				continue
+			}
+			profile := rearv1alpha1.MIGProfileResources{Profile: strings.TrimPrefix(string(name), migResourcePrefix)}
+			profile.Allocatable, profile.Allocated, profile.Available = gpuFigures(model.allocatable, model.allocated, name)
+			gpuModel.MIGProfiles = append(gpuModel.MIGProfiles, profile)
+		}
+		sort.Slice(gpuModel.MIGProfiles, func(i, j int) bool {
+			return gpuModel.MIGProfiles[i].Profile < gpuModel.MIGProfiles[j].Profile
+		})
+
+		result = append(result, gpuModel)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Product != result[j].Product {
+			return result[i].Product < result[j].Product
+		}
+		return result[i].MIGStrategy < result[j].MIGStrategy
+	})
+
+	return result
+}
+
+// gpuFigures returns the allocatable, allocated and available amounts of a GPU resource
+func gpuFigures(allocatable, allocated corev1.ResourceList, name corev1.ResourceName) (resource.Quantity, resource.Quantity, resource.Quantity) {
+	total := resource.NewQuantity(0, resource.DecimalSI)
+	if quantity, ok := allocatable[name]; ok {
+		total.Add(quantity)
+	}
+	used := resource.NewQuantity(0, resource.DecimalSI)
+	if quantity, ok := allocated[name]; ok {
+		used.Add(quantity)
+	}
+	free := total.DeepCopy()
+	free.Sub(*used)
+	return *total, *used, free
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// defaultNodePool groups the nodes that do not carry the node pool label
+const defaultNodePool = "default"
+
+// CollectNodePools breaks the advertised resources down by node pool.
+// It returns nil when no NodePoolLabel is configured.
+//
+// Reservations from ProviderInstructions are cluster-wide and cannot be attributed
+// to a pool, so each pool's Available is its Allocatable minus its Allocated only.
+func (c *Collector) CollectNodePools(snapshot *Snapshot) []rearv1alpha1.NodePoolResources {
+	if c.NodePoolLabel == "" {
+		return nil
+	}
+
+	nodes := snapshot.nodes
+
+	type poolTotals struct {
+		nodeCount   int32
+		capacity    corev1.ResourceList
+		allocatable corev1.ResourceList
+		allocated   corev1.ResourceList
+	}
+
+	pools := map[string]*poolTotals{}
+	for _, node := range nodes {
+		name := node.labels[c.NodePoolLabel]
+		if name == "" {
+			name = defaultNodePool
+		}
+
+		pool, ok := pools[name]
+		if !ok {
+			pool = &poolTotals{
+				capacity:    corev1.ResourceList{},
+				allocatable: corev1.ResourceList{},
+				allocated:   corev1.ResourceList{},
+			}
+			pools[name] = pool
+		}
+
+		pool.nodeCount++
+		c.addResources(pool.capacity, node.capacity)
+		c.addResources(pool.allocatable, node.allocatable)
+		c.addResources(pool.allocated, node.allocated)
+	}
+
+	result := make([]rearv1alpha1.NodePoolResources, 0, len(pools))
+	for name, pool := range pools {
+		available := pool.allocatable.DeepCopy()
+		subtractResources(available, pool.allocated)
+
+		result = append(result, rearv1alpha1.NodePoolResources{
+			Name:        name,
+			NodeCount:   pool.nodeCount,
+			Capacity:    c.toResourceQuantities(pool.capacity),
+			Allocatable: c.toResourceQuantities(pool.allocatable),
+			Allocated:   c.toResourceQuantities(pool.allocated),
+			Available:   c.toResourceQuantities(available),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result
+}
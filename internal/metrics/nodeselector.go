@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Labels identifying control-plane nodes
+const (
+	controlPlaneLabel       = "node-role.kubernetes.io/control-plane"
+	legacyControlPlaneLabel = "node-role.kubernetes.io/master"
+)
+
+// Markers of the virtual nodes Liqo creates for peered remote clusters
+const (
+	liqoNodeTypeLabel    = "liqo.io/type"
+	liqoVirtualNodeType  = "virtual-node"
+	liqoProviderIDPrefix = "liqo://"
+)
+
+// NodeSelector decides which ready nodes contribute to the advertised capacity.
+// A nil NodeSelector selects every node.
+type NodeSelector struct {
+	// LabelSelector restricts advertised nodes to those matching it (nil matches all)
+	LabelSelector labels.Selector
+
+	// ExcludeControlPlane skips nodes carrying a control-plane role label
+	ExcludeControlPlane bool
+
+	// ExcludeUnschedulable skips cordoned nodes (spec.unschedulable)
+	ExcludeUnschedulable bool
+
+	// ExcludeTainted skips nodes with a NoSchedule or NoExecute taint not tolerated by Tolerations
+	ExcludeTainted bool
+
+	// Tolerations lists the taints that do not exclude a node when ExcludeTainted is set
+	Tolerations []corev1.Toleration
+}
+
+// Selects reports whether the node should be advertised
+func (s *NodeSelector) Selects(node *corev1.Node) bool {
+	if s == nil {
+		return true
+	}
+
+	if s.LabelSelector != nil && !s.LabelSelector.Matches(labels.Set(node.Labels)) {
+		return false
+	}
+
+	if s.ExcludeControlPlane {
+		if _, ok := node.Labels[controlPlaneLabel]; ok {
+			return false
+		}
+		if _, ok := node.Labels[legacyControlPlaneLabel]; ok {
+			return false
+		}
+	}
+
+	if s.ExcludeUnschedulable && node.Spec.Unschedulable {
+		return false
+	}
+
+	if s.ExcludeTainted {
+		for i := range node.Spec.Taints {
+			taint := &node.Spec.Taints[i]
+			if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+				continue
+			}
+			if !s.tolerates(taint) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// tolerates reports whether one of the configured tolerations matches the taint
+func (s *NodeSelector) tolerates(taint *corev1.Taint) bool {
+	for i := range s.Tolerations {
+		if s.Tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// isVirtualNode reports whether the node is a Liqo virtual node standing for a remote cluster
+func isVirtualNode(node *corev1.Node) bool {
+	return node.Labels[liqoNodeTypeLabel] == liqoVirtualNodeType ||
+		strings.HasPrefix(node.Spec.ProviderID, liqoProviderIDPrefix)
+}
+
+// ParseTolerations parses a comma-separated list of tolerations in the
+// "key[=value][:effect]" form. A toleration without value matches any value
+// of the key, and one without effect matches every effect.
+func ParseTolerations(value string) ([]corev1.Toleration, error) {
+	var tolerations []corev1.Toleration
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		toleration := corev1.Toleration{Operator: corev1.TolerationOpExists}
+
+		if keyValue, effect, ok := strings.Cut(item, ":"); ok {
+			switch corev1.TaintEffect(effect) {
+			case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+				toleration.Effect = corev1.TaintEffect(effect)
+			default:
+				return nil, fmt.Errorf("invalid taint effect %q in toleration %q", effect, item)
+			}
+			item = keyValue
+		}
+
+		if key, val, ok := strings.Cut(item, "="); ok {
+			toleration.Key = key
+			toleration.Value = val
+			toleration.Operator = corev1.TolerationOpEqual
+		} else {
+			toleration.Key = item
+		}
+
+		if toleration.Key == "" {
+			return nil, fmt.Errorf("missing key in toleration %q", item)
+		}
+
+		tolerations = append(tolerations, toleration)
+	}
+
+	return tolerations, nil
+}
@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// PodAccountingPolicy decides which pods count toward the allocated totals.
+// The zero value counts only live pods bound to advertised nodes.
+type PodAccountingPolicy struct {
+	// IncludeTerminating keeps counting pods with a deletionTimestamp until they are removed
+	IncludeTerminating bool
+
+	// IncludeUnscheduled counts Pending pods not yet bound to any node as allocated
+	IncludeUnscheduled bool
+
+	// ReportPending publishes the requests of unscheduled pods as a separate Pending quantity
+	ReportPending bool
+}
+
+// isPreemptible reports whether a pod runs below the configured preemption priority threshold
+func (c *Collector) isPreemptible(pod *corev1.Pod) bool {
+	if c.PreemptiblePriorityThreshold == nil {
+		return false
+	}
+	var priority int32
+	if pod.Spec.Priority != nil {
+		priority = *pod.Spec.Priority
+	}
+	return priority < *c.PreemptiblePriorityThreshold
+}
+
+// podLevelResources are the resources that pod-level spec.resources may set
+var podLevelResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// podRequests returns the resources a pod holds on its node, or nil if the pod is not counted.
+// The computation follows the kube-scheduler:
+//   - regular containers run concurrently, so their requests are summed
+//   - native sidecars (init containers with restartPolicy Always) keep running once
+//     started, so they are summed too and stay in place for the init containers after them
+//   - other init containers run one at a time; each needs its own requests plus the
+//     sidecars started before it, and the pod needs the largest of those
+//   - pod-level spec.resources override the container sums for the resources it sets
+//   - the pod overhead is added on top
+//   - the pod holds one slot of the node's "pods" allocatable
+func (c *Collector) podRequests(pod *corev1.Pod) corev1.ResourceList {
+	if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
+		return nil
+	}
+	if pod.DeletionTimestamp != nil && !c.PodAccounting.IncludeTerminating {
+		return nil
+	}
+
+	statuses := make(map[string]*corev1.ContainerStatus, len(pod.Status.ContainerStatuses)+len(pod.Status.InitContainerStatuses))
+	for i := range pod.Status.ContainerStatuses {
+		statuses[pod.Status.ContainerStatuses[i].Name] = &pod.Status.ContainerStatuses[i]
+	}
+	for i := range pod.Status.InitContainerStatuses {
+		statuses[pod.Status.InitContainerStatuses[i].Name] = &pod.Status.InitContainerStatuses[i]
+	}
+	infeasible := isResizeInfeasible(pod)
+
+	requests := corev1.ResourceList{}
+	for i := range pod.Spec.Containers {
+		c.addResources(requests, c.containerRequests(&pod.Spec.Containers[i], statuses[pod.Spec.Containers[i].Name], infeasible))
+	}
+
+	sidecars := corev1.ResourceList{}
+	initMax := corev1.ResourceList{}
+	for i := range pod.Spec.InitContainers {
+		initContainer := &pod.Spec.InitContainers[i]
+		containerRequests := c.containerRequests(initContainer, statuses[initContainer.Name], infeasible)
+
+		if isSidecar(initContainer) {
+			c.addResources(requests, containerRequests)
+			c.addResources(sidecars, containerRequests)
+			c.maxResources(initMax, sidecars)
+			continue
+		}
+
+		c.addResources(containerRequests, sidecars)
+		c.maxResources(initMax, containerRequests)
+	}
+	c.maxResources(requests, initMax)
+
+	if pod.Spec.Resources != nil {
+		for _, name := range podLevelResources {
+			if quantity, ok := pod.Spec.Resources.Requests[name]; ok {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+
+	if pod.Spec.Overhead != nil {
+		c.addResources(requests, pod.Spec.Overhead)
+	}
+
+	// Every pod takes one of the node's pod slots
+	requests[corev1.ResourcePods] = *resource.NewQuantity(1, resource.DecimalSI)
+
+	return requests
+}
+
+// containerRequests returns the requests of a single container. For pods resized in
+// place, the kubelet may hold more than the spec asks for until the resize is
+// actuated, so the larger of the spec and the status figures is used; when the
+// resize was rejected as infeasible the spec no longer applies and only the status counts.
+func (c *Collector) containerRequests(container *corev1.Container, status *corev1.ContainerStatus, infeasible bool) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	if status == nil || (status.Resources == nil && len(status.AllocatedResources) == 0) {
+		c.addResources(requests, container.Resources.Requests)
+		return requests
+	}
+
+	if !infeasible {
+		c.addResources(requests, container.Resources.Requests)
+	}
+	if status.Resources != nil {
+		c.maxResources(requests, status.Resources.Requests)
+	}
+	c.maxResources(requests, status.AllocatedResources)
+
+	return requests
+}
+
+// isSidecar reports whether an init container is a native sidecar
+func isSidecar(container *corev1.Container) bool {
+	return container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways
+}
+
+// isResizeInfeasible reports whether the kubelet rejected the pod's pending in-place resize
+func isResizeInfeasible(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodResizePending {
+			return condition.Reason == corev1.PodReasonInfeasible
+		}
+	}
+	return false
+}
@@ -0,0 +1,275 @@
+package metrics
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// resources builds a resource list from name and quantity pairs
+func resources(pairs ...string) corev1.ResourceList {
+	list := corev1.ResourceList{}
+	for i := 0; i < len(pairs); i += 2 {
+		list[corev1.ResourceName(pairs[i])] = resource.MustParse(pairs[i+1])
+	}
+	return list
+}
+
+// container builds a container requesting cpu and memory
+func container(name, cpu, memory string) corev1.Container {
+	return corev1.Container{
+		Name:      name,
+		Resources: corev1.ResourceRequirements{Requests: resources("cpu", cpu, "memory", memory)},
+	}
+}
+
+// sidecar builds a native sidecar requesting cpu and memory
+func sidecar(name, cpu, memory string) corev1.Container {
+	always := corev1.ContainerRestartPolicyAlways
+	c := container(name, cpu, memory)
+	c.RestartPolicy = &always
+	return c
+}
+
+// runningPod builds a running pod with the given containers
+func runningPod(initContainers, containers []corev1.Container) *corev1.Pod {
+	return &corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: initContainers,
+			Containers:     containers,
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+// withStatus sets the status of the named container
+func withStatus(pod *corev1.Pod, name string, statusRequests, allocated corev1.ResourceList) *corev1.Pod {
+	status := corev1.ContainerStatus{Name: name, AllocatedResources: allocated}
+	if statusRequests != nil {
+		status.Resources = &corev1.ResourceRequirements{Requests: statusRequests}
+	}
+	pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, status)
+	return pod
+}
+
+// withResizePending marks an in-place resize of the pod as pending for the given reason
+func withResizePending(pod *corev1.Pod, reason string) *corev1.Pod {
+	pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+		Type:   corev1.PodResizePending,
+		Status: corev1.ConditionTrue,
+		Reason: reason,
+	})
+	return pod
+}
+
+func TestPodRequests(t *testing.T) {
+	now := metav1.Now()
+
+	tests := []struct {
+		name       string
+		accounting PodAccountingPolicy
+		pod        *corev1.Pod
+		// want is nil when the pod is not counted; the pods slot is checked separately
+		want corev1.ResourceList
+	}{
+		{
+			name: "regular containers are summed",
+			pod: runningPod(nil, []corev1.Container{
+				container("a", "1", "1Gi"),
+				container("b", "500m", "512Mi"),
+			}),
+			want: resources("cpu", "1500m", "memory", "1536Mi"),
+		},
+		{
+			name: "init container larger than the containers sets the request",
+			pod: runningPod(
+				[]corev1.Container{container("init", "2", "256Mi")},
+				[]corev1.Container{container("app", "1", "1Gi")},
+			),
+			want: resources("cpu", "2", "memory", "1Gi"),
+		},
+		{
+			name: "init containers run one at a time, so only the largest counts",
+			pod: runningPod(
+				[]corev1.Container{
+					container("init-a", "3", "128Mi"),
+					container("init-b", "1", "2Gi"),
+				},
+				[]corev1.Container{container("app", "1", "1Gi")},
+			),
+			want: resources("cpu", "3", "memory", "2Gi"),
+		},
+		{
+			name: "sidecar started before an init container runs alongside it",
+			pod: runningPod(
+				[]corev1.Container{
+					sidecar("proxy", "1", "128Mi"),
+					container("init", "2", "128Mi"),
+				},
+				[]corev1.Container{container("app", "1", "1Gi")},
+			),
+			// init needs 2 + 1 of the sidecar; the containers need 1 + 1
+			want: resources("cpu", "3", "memory", "1152Mi"),
+		},
+		{
+			name: "sidecar started after an init container does not add to it",
+			pod: runningPod(
+				[]corev1.Container{
+					container("init", "2", "128Mi"),
+					sidecar("proxy", "1", "128Mi"),
+				},
+				[]corev1.Container{container("app", "1", "1Gi")},
+			),
+			want: resources("cpu", "2", "memory", "1152Mi"),
+		},
+		{
+			name: "sidecars accumulate for the init containers after them",
+			pod: runningPod(
+				[]corev1.Container{
+					sidecar("proxy", "1", "100Mi"),
+					sidecar("logger", "1", "100Mi"),
+					container("init", "1", "100Mi"),
+				},
+				[]corev1.Container{container("app", "500m", "100Mi")},
+			),
+			// init needs 1 + 2 of the sidecars; the containers need 0.5 + 2
+			want: resources("cpu", "3", "memory", "300Mi"),
+		},
+		{
+			name: "pod-level resources override the container sums",
+			pod: func() *corev1.Pod {
+				pod := runningPod(nil, []corev1.Container{container("app", "1", "1Gi")})
+				pod.Spec.Resources = &corev1.ResourceRequirements{Requests: resources("cpu", "4")}
+				return pod
+			}(),
+			want: resources("cpu", "4", "memory", "1Gi"),
+		},
+		{
+			name: "overhead is added on top",
+			pod: func() *corev1.Pod {
+				pod := runningPod(nil, []corev1.Container{container("app", "1", "1Gi")})
+				pod.Spec.Overhead = resources("cpu", "250m", "memory", "64Mi")
+				return pod
+			}(),
+			want: resources("cpu", "1250m", "memory", "1088Mi"),
+		},
+		{
+			name: "overhead is added on top of pod-level resources",
+			pod: func() *corev1.Pod {
+				pod := runningPod(nil, []corev1.Container{container("app", "1", "1Gi")})
+				pod.Spec.Resources = &corev1.ResourceRequirements{Requests: resources("cpu", "2", "memory", "2Gi")}
+				pod.Spec.Overhead = resources("cpu", "250m", "memory", "64Mi")
+				return pod
+			}(),
+			want: resources("cpu", "2250m", "memory", "2112Mi"),
+		},
+		{
+			name: "pending resize up counts the new spec",
+			pod: withResizePending(withStatus(
+				runningPod(nil, []corev1.Container{container("app", "2", "1Gi")}),
+				"app", resources("cpu", "1", "memory", "1Gi"), resources("cpu", "1", "memory", "1Gi"),
+			), corev1.PodReasonDeferred),
+			want: resources("cpu", "2", "memory", "1Gi"),
+		},
+		{
+			name: "pending resize down keeps the allocated resources",
+			pod: withResizePending(withStatus(
+				runningPod(nil, []corev1.Container{container("app", "1", "1Gi")}),
+				"app", resources("cpu", "2", "memory", "1Gi"), resources("cpu", "2", "memory", "1Gi"),
+			), corev1.PodReasonDeferred),
+			want: resources("cpu", "2", "memory", "1Gi"),
+		},
+		{
+			name: "infeasible resize only counts the status",
+			pod: withResizePending(withStatus(
+				runningPod(nil, []corev1.Container{container("app", "4", "1Gi")}),
+				"app", resources("cpu", "1", "memory", "1Gi"), resources("cpu", "1", "memory", "1Gi"),
+			), corev1.PodReasonInfeasible),
+			want: resources("cpu", "1", "memory", "1Gi"),
+		},
+		{
+			name: "allocated resources above the status resources win",
+			pod: withStatus(
+				runningPod(nil, []corev1.Container{container("app", "1", "1Gi")}),
+				"app", resources("cpu", "2", "memory", "1Gi"), resources("cpu", "3", "memory", "1Gi"),
+			),
+			want: resources("cpu", "3", "memory", "1Gi"),
+		},
+		{
+			name: "status resources above the allocated resources win",
+			pod: withStatus(
+				runningPod(nil, []corev1.Container{container("app", "1", "1Gi")}),
+				"app", resources("cpu", "3", "memory", "1Gi"), resources("cpu", "2", "memory", "1Gi"),
+			),
+			want: resources("cpu", "3", "memory", "1Gi"),
+		},
+		{
+			name: "allocated resources without status resources are used",
+			pod: withStatus(
+				runningPod(nil, []corev1.Container{container("app", "1", "1Gi")}),
+				"app", nil, resources("cpu", "2", "memory", "2Gi"),
+			),
+			want: resources("cpu", "2", "memory", "2Gi"),
+		},
+		{
+			name: "completed pods are not counted",
+			pod: func() *corev1.Pod {
+				pod := runningPod(nil, []corev1.Container{container("app", "1", "1Gi")})
+				pod.Status.Phase = corev1.PodSucceeded
+				return pod
+			}(),
+		},
+		{
+			name: "terminating pods are not counted by default",
+			pod: func() *corev1.Pod {
+				pod := runningPod(nil, []corev1.Container{container("app", "1", "1Gi")})
+				pod.DeletionTimestamp = &now
+				return pod
+			}(),
+		},
+		{
+			name:       "terminating pods are counted when included",
+			accounting: PodAccountingPolicy{IncludeTerminating: true},
+			pod: func() *corev1.Pod {
+				pod := runningPod(nil, []corev1.Container{container("app", "1", "1Gi")})
+				pod.DeletionTimestamp = &now
+				return pod
+			}(),
+			want: resources("cpu", "1", "memory", "1Gi"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := &Collector{PodAccounting: tt.accounting}
+			got := collector.podRequests(tt.pod)
+
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("expected the pod not to be counted, got %v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("expected the pod to be counted")
+			}
+
+			pods := got[corev1.ResourcePods]
+			if pods.Value() != 1 {
+				t.Errorf("pods = %s, want 1", pods.String())
+			}
+			delete(got, corev1.ResourcePods)
+
+			if len(got) != len(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			for name, want := range tt.want {
+				if quantity := got[name]; quantity.Cmp(want) != 0 {
+					t.Errorf("%s = %s, want %s", name, quantity.String(), want.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// quotaRequestsPrefix marks the ResourceQuota entries that bound resource requests
+const quotaRequestsPrefix = "requests."
+
+// quotaRemaining returns the quota left for offloaded workloads, summed over the
+// offload namespaces. Within a namespace the tightest ResourceQuota wins; a resource
+// is only returned when every offload namespace limits it, since a single unlimited
+// namespace leaves the total unbounded. It returns nil when no namespace is configured.
+func (c *Collector) quotaRemaining(ctx context.Context) (corev1.ResourceList, error) {
+	var total corev1.ResourceList
+
+	for i, namespace := range c.OffloadNamespaces {
+		quotas := &corev1.ResourceQuotaList{}
+		if err := c.Client.List(ctx, quotas, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list resource quotas in %s: %w", namespace, err)
+		}
+
+		remaining := corev1.ResourceList{}
+		for _, quota := range quotas.Items {
+			for quotaName, hard := range quota.Status.Hard {
+				name, ok := quotaResourceName(quotaName)
+				if !ok || !c.isTracked(name) {
+					continue
+				}
+
+				left := hard.DeepCopy()
+				if used, ok := quota.Status.Used[quotaName]; ok {
+					left.Sub(used)
+				}
+				if left.Sign() < 0 {
+					left = *resource.NewQuantity(0, hard.Format)
+				}
+
+				if current, ok := remaining[name]; !ok || left.Cmp(current) < 0 {
+					remaining[name] = left
+				}
+			}
+		}
+
+		if i == 0 {
+			total = remaining
+			continue
+		}
+		for name, quantity := range total {
+			left, ok := remaining[name]
+			if !ok {
+				delete(total, name)
+				continue
+			}
+			quantity.Add(left)
+			total[name] = quantity
+		}
+	}
+
+	return total, nil
+}
+
+// quotaResourceName maps a ResourceQuota entry to the resource whose requests it bounds.
+// Limits, object counts and per-StorageClass entries do not bound advertised requests.
+func quotaResourceName(name corev1.ResourceName) (corev1.ResourceName, bool) {
+	if requested, ok := strings.CutPrefix(string(name), quotaRequestsPrefix); ok {
+		return corev1.ResourceName(requested), true
+	}
+
+	switch name {
+	case corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage, corev1.ResourcePods:
+		return name, true
+	}
+	return "", false
+}
@@ -0,0 +1,303 @@
+package metrics
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// nodeEntry is the contribution of a single node to the cluster totals
+type nodeEntry struct {
+	counted     bool
+	excluded    bool
+	borrowed    bool
+	labels      map[string]string
+	capacity    corev1.ResourceList
+	allocatable corev1.ResourceList
+}
+
+// podEntry is the contribution of a single pod to the cluster totals
+type podEntry struct {
+	nodeName    string
+	preemptible bool
+	requests    corev1.ResourceList
+}
+
+// clusterState keeps per-node and per-cluster running totals.
+// Upserts replace the previous contribution of an object, so replaying the same
+// event twice (e.g. an informer event racing with a resync) never double counts.
+//
+// The cluster allocated total only includes pods bound to counted nodes, plus pods
+// not bound to any node yet when the accounting policy includes them; it always
+// equals the sum of nodeAllocated over those.
+type clusterState struct {
+	collector *Collector
+
+	nodes map[string]nodeEntry
+	pods  map[types.UID]podEntry
+
+	// nodeAllocated is kept apart from nodes so pods bound to a node that is
+	// not (yet) known are still accounted once the node appears.
+	// Pods not bound to any node are kept under the empty name.
+	nodeAllocated map[string]corev1.ResourceList
+
+	// nodePreemptible is the share of nodeAllocated held by pods below the preemption threshold
+	nodePreemptible map[string]corev1.ResourceList
+
+	excludedNodes int
+	capacity      corev1.ResourceList
+	allocatable   corev1.ResourceList
+	allocated     corev1.ResourceList
+	preemptible   corev1.ResourceList
+
+	// borrowed is the capacity of ready Liqo virtual nodes, i.e. resources of remote clusters
+	borrowed corev1.ResourceList
+}
+
+// clusterSnapshot is a point-in-time copy of the cluster totals
+type clusterSnapshot struct {
+	// nodeCount is the number of known nodes, whether counted or not
+	nodeCount int
+	// excludedNodes is the number of ready nodes left out by the node selection policy
+	excludedNodes int
+	capacity      corev1.ResourceList
+	allocatable   corev1.ResourceList
+	allocated     corev1.ResourceList
+	// preemptible is the share of allocated held by pods below the preemption threshold
+	preemptible corev1.ResourceList
+	borrowed    corev1.ResourceList
+	// pending is the sum of requests of pods not bound to any node yet
+	pending corev1.ResourceList
+}
+
+// Snapshot is a point-in-time view of the cluster, taken once per reconcile so that
+// every figure of an Advertisement is computed from the same nodes and pods.
+//
+// Besides the cluster totals it holds one entry per counted node, which the node pool,
+// fragmentation and GPU breakdowns need, so taking it is O(nodes) rather than O(1).
+// That is still far below the O(pods) List it replaces, and only the per-node allocated
+// totals are copied: node capacity, allocatable and labels are replaced, never modified,
+// by upsertNode, so the snapshot shares them with the state. Collectors must not modify them.
+type Snapshot struct {
+	cluster *clusterSnapshot
+	nodes   []nodeSnapshot
+}
+
+// nodeSnapshot is a point-in-time copy of a counted node and the pods bound to it
+type nodeSnapshot struct {
+	name        string
+	labels      map[string]string
+	capacity    corev1.ResourceList
+	allocatable corev1.ResourceList
+	allocated   corev1.ResourceList
+}
+
+// newClusterState creates an empty state using the collector's accounting rules
+func newClusterState(collector *Collector) *clusterState {
+	return &clusterState{
+		collector:       collector,
+		nodes:           map[string]nodeEntry{},
+		pods:            map[types.UID]podEntry{},
+		nodeAllocated:   map[string]corev1.ResourceList{},
+		nodePreemptible: map[string]corev1.ResourceList{},
+		capacity:        corev1.ResourceList{},
+		allocatable:     corev1.ResourceList{},
+		allocated:       corev1.ResourceList{},
+		preemptible:     corev1.ResourceList{},
+		borrowed:        corev1.ResourceList{},
+	}
+}
+
+// isCounted reports whether pods bound to the named node contribute to the cluster totals
+func (s *clusterState) isCounted(nodeName string) bool {
+	if nodeName == "" {
+		return s.collector.PodAccounting.IncludeUnscheduled
+	}
+	return s.nodes[nodeName].counted
+}
+
+// upsertNode replaces the contribution of a node
+func (s *clusterState) upsertNode(node *corev1.Node) {
+	s.deleteNode(node.Name)
+
+	// Virtual nodes represent remote clusters: their capacity is borrowed, never ours to lend
+	ready := isNodeReady(node)
+	virtual := isVirtualNode(node)
+	selected := s.collector.NodeSelector.Selects(node)
+	entry := nodeEntry{
+		counted:     ready && !virtual && selected,
+		excluded:    ready && !virtual && !selected,
+		borrowed:    ready && virtual,
+		labels:      node.Labels,
+		capacity:    corev1.ResourceList{},
+		allocatable: corev1.ResourceList{},
+	}
+	s.collector.addResources(entry.capacity, node.Status.Capacity)
+	s.collector.addResources(entry.allocatable, node.Status.Allocatable)
+
+	if entry.counted {
+		s.collector.addResources(s.capacity, entry.capacity)
+		s.collector.addResources(s.allocatable, entry.allocatable)
+		if nodeAllocated, ok := s.nodeAllocated[node.Name]; ok {
+			s.collector.addResources(s.allocated, nodeAllocated)
+		}
+		if nodePreemptible, ok := s.nodePreemptible[node.Name]; ok {
+			s.collector.addResources(s.preemptible, nodePreemptible)
+		}
+	}
+	if entry.excluded {
+		s.excludedNodes++
+	}
+	if entry.borrowed {
+		s.collector.addResources(s.borrowed, entry.capacity)
+	}
+	s.nodes[node.Name] = entry
+}
+
+// deleteNode removes the contribution of a node
+func (s *clusterState) deleteNode(name string) {
+	entry, ok := s.nodes[name]
+	if !ok {
+		return
+	}
+	if entry.counted {
+		releaseResources(s.capacity, entry.capacity)
+		releaseResources(s.allocatable, entry.allocatable)
+		if nodeAllocated, ok := s.nodeAllocated[name]; ok {
+			releaseResources(s.allocated, nodeAllocated)
+		}
+		if nodePreemptible, ok := s.nodePreemptible[name]; ok {
+			releaseResources(s.preemptible, nodePreemptible)
+		}
+	}
+	if entry.excluded {
+		s.excludedNodes--
+	}
+	if entry.borrowed {
+		releaseResources(s.borrowed, entry.capacity)
+	}
+	delete(s.nodes, name)
+}
+
+// upsertPod replaces the contribution of a pod
+func (s *clusterState) upsertPod(pod *corev1.Pod) {
+	s.deletePod(pod.UID)
+
+	requests := s.collector.podRequests(pod)
+	if requests == nil {
+		return
+	}
+
+	entry := podEntry{
+		nodeName:    pod.Spec.NodeName,
+		preemptible: s.collector.isPreemptible(pod),
+		requests:    requests,
+	}
+	counted := s.isCounted(entry.nodeName)
+
+	addNodeResources(s.collector, s.nodeAllocated, entry.nodeName, entry.requests)
+	if counted {
+		s.collector.addResources(s.allocated, entry.requests)
+	}
+	if entry.preemptible {
+		addNodeResources(s.collector, s.nodePreemptible, entry.nodeName, entry.requests)
+		if counted {
+			s.collector.addResources(s.preemptible, entry.requests)
+		}
+	}
+	s.pods[pod.UID] = entry
+}
+
+// deletePod removes the contribution of a pod
+func (s *clusterState) deletePod(uid types.UID) {
+	entry, ok := s.pods[uid]
+	if !ok {
+		return
+	}
+	counted := s.isCounted(entry.nodeName)
+
+	if counted {
+		releaseResources(s.allocated, entry.requests)
+	}
+	releaseNodeResources(s.nodeAllocated, entry.nodeName, entry.requests)
+	if entry.preemptible {
+		if counted {
+			releaseResources(s.preemptible, entry.requests)
+		}
+		releaseNodeResources(s.nodePreemptible, entry.nodeName, entry.requests)
+	}
+	delete(s.pods, uid)
+}
+
+// view copies the cluster and per-node totals; see Snapshot for what is shared
+func (s *clusterState) view() *Snapshot {
+	return &Snapshot{cluster: s.snapshot(), nodes: s.nodeSnapshots()}
+}
+
+// snapshot copies the cluster totals
+func (s *clusterState) snapshot() *clusterSnapshot {
+	return &clusterSnapshot{
+		nodeCount:     len(s.nodes),
+		excludedNodes: s.excludedNodes,
+		capacity:      s.capacity.DeepCopy(),
+		allocatable:   s.allocatable.DeepCopy(),
+		allocated:     s.allocated.DeepCopy(),
+		preemptible:   s.preemptible.DeepCopy(),
+		borrowed:      s.borrowed.DeepCopy(),
+		pending:       s.nodeAllocated[""].DeepCopy(),
+	}
+}
+
+// nodeSnapshots copies the allocated totals of every counted node, sharing the rest
+func (s *clusterState) nodeSnapshots() []nodeSnapshot {
+	nodes := make([]nodeSnapshot, 0, len(s.nodes))
+	for name, entry := range s.nodes {
+		if !entry.counted {
+			continue
+		}
+		allocated := corev1.ResourceList{}
+		if nodeAllocated, ok := s.nodeAllocated[name]; ok {
+			allocated = nodeAllocated.DeepCopy()
+		}
+		nodes = append(nodes, nodeSnapshot{
+			name:        name,
+			labels:      entry.labels,
+			capacity:    entry.capacity,
+			allocatable: entry.allocatable,
+			allocated:   allocated,
+		})
+	}
+	return nodes
+}
+
+// addNodeResources adds a pod contribution to the per-node totals of its node
+func addNodeResources(collector *Collector, totals map[string]corev1.ResourceList, nodeName string, contribution corev1.ResourceList) {
+	total, ok := totals[nodeName]
+	if !ok {
+		total = corev1.ResourceList{}
+		totals[nodeName] = total
+	}
+	collector.addResources(total, contribution)
+}
+
+// releaseNodeResources removes a pod contribution from the per-node totals of its node
+func releaseNodeResources(totals map[string]corev1.ResourceList, nodeName string, contribution corev1.ResourceList) {
+	total, ok := totals[nodeName]
+	if !ok {
+		return
+	}
+	releaseResources(total, contribution)
+	if len(total) == 0 {
+		delete(totals, nodeName)
+	}
+}
+
+// releaseResources subtracts a previous contribution from a running total and drops
+// resources that fell to zero, so totals match what a full rebuild would produce
+func releaseResources(total, contribution corev1.ResourceList) {
+	subtractResources(total, contribution)
+	for name := range contribution {
+		if quantity, ok := total[name]; ok && quantity.IsZero() {
+			delete(total, name)
+		}
+	}
+}
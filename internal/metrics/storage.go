@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// storageClassTotals is the persistent storage offered through a single StorageClass
+type storageClassTotals struct {
+	name        string
+	provisioner string
+	// tracked is set when the driver publishes CSIStorageCapacity objects for the class
+	tracked bool
+	// segments is the capacity left for new volumes in each topology segment of the class
+	segments  map[string]resource.Quantity
+	available resource.Quantity
+	allocated resource.Quantity
+}
+
+// storageClassTotals sums, per StorageClass, the capacity published through
+// CSIStorageCapacity and the capacity of bound PersistentVolumeClaims.
+// CSIStorageCapacity reports what is left for new volumes in each topology
+// segment, so the class capacity is that amount plus what is already bound.
+// A segment reported more than once for the same class is only counted once.
+func (c *Collector) storageClassTotals(ctx context.Context) ([]storageClassTotals, error) {
+	storageClasses := &storagev1.StorageClassList{}
+	if err := c.Client.List(ctx, storageClasses); err != nil {
+		return nil, fmt.Errorf("failed to list storage classes: %w", err)
+	}
+
+	capacities := &storagev1.CSIStorageCapacityList{}
+	if err := c.Client.List(ctx, capacities); err != nil {
+		return nil, fmt.Errorf("failed to list CSI storage capacities: %w", err)
+	}
+
+	claims := &corev1.PersistentVolumeClaimList{}
+	if err := c.Client.List(ctx, claims); err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %w", err)
+	}
+
+	classes := make(map[string]*storageClassTotals, len(storageClasses.Items))
+	for _, storageClass := range storageClasses.Items {
+		classes[storageClass.Name] = &storageClassTotals{
+			name:        storageClass.Name,
+			provisioner: storageClass.Provisioner,
+			segments:    map[string]resource.Quantity{},
+			available:   *resource.NewQuantity(0, resource.BinarySI),
+			allocated:   *resource.NewQuantity(0, resource.BinarySI),
+		}
+	}
+
+	for _, capacity := range capacities.Items {
+		class, ok := classes[capacity.StorageClassName]
+		if !ok {
+			continue
+		}
+		class.tracked = true
+		if capacity.Capacity == nil {
+			continue
+		}
+		segment := metav1.FormatLabelSelector(capacity.NodeTopology)
+		if current, ok := class.segments[segment]; !ok || capacity.Capacity.Cmp(current) > 0 {
+			class.segments[segment] = capacity.Capacity.DeepCopy()
+		}
+	}
+	for _, class := range classes {
+		for _, free := range class.segments {
+			class.available.Add(free)
+		}
+	}
+
+	for _, claim := range claims.Items {
+		if claim.Status.Phase != corev1.ClaimBound || claim.Spec.StorageClassName == nil {
+			continue
+		}
+		class, ok := classes[*claim.Spec.StorageClassName]
+		if !ok {
+			continue
+		}
+		if size, ok := claim.Status.Capacity[corev1.ResourceStorage]; ok {
+			class.allocated.Add(size)
+		}
+	}
+
+	result := make([]storageClassTotals, 0, len(classes))
+	for _, class := range classes {
+		result = append(result, *class)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].name < result[j].name })
+
+	return result, nil
+}
+
+// addPersistentStorage adds the storage of classes with known capacity to the cluster totals.
+// Classes of the same provisioner draw new volumes from the same backend, so the free
+// space of a topology segment shared by several of them is counted once, at its largest.
+func (c *Collector) addPersistentStorage(ctx context.Context, capacity, allocatable, allocated corev1.ResourceList) error {
+	classes, err := c.storageClassTotals(ctx)
+	if err != nil {
+		return err
+	}
+
+	type backendSegment struct {
+		provisioner string
+		segment     string
+	}
+	free := map[backendSegment]resource.Quantity{}
+
+	total := corev1.ResourceList{}
+	used := corev1.ResourceList{}
+	for _, class := range classes {
+		// Without CSIStorageCapacity the free space is unknown, so the class is left out
+		if !class.tracked {
+			continue
+		}
+		for segment, quantity := range class.segments {
+			key := backendSegment{provisioner: class.provisioner, segment: segment}
+			if current, ok := free[key]; !ok || quantity.Cmp(current) > 0 {
+				free[key] = quantity
+			}
+		}
+		c.addResources(total, corev1.ResourceList{corev1.ResourceStorage: class.allocated})
+		c.addResources(used, corev1.ResourceList{corev1.ResourceStorage: class.allocated})
+	}
+	for _, quantity := range free {
+		c.addResources(total, corev1.ResourceList{corev1.ResourceStorage: quantity})
+	}
+
+	c.addResources(capacity, total)
+	c.addResources(allocatable, total)
+	c.addResources(allocated, used)
+	return nil
+}
+
+// CollectStorageClasses breaks persistent storage down by StorageClass.
+// It returns nil when storage advertisement is disabled.
+func (c *Collector) CollectStorageClasses(ctx context.Context) ([]rearv1alpha1.StorageClassResources, error) {
+	if !c.AdvertiseStorage {
+		return nil, nil
+	}
+
+	classes, err := c.storageClassTotals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]rearv1alpha1.StorageClassResources, 0, len(classes))
+	for _, class := range classes {
+		resources := rearv1alpha1.StorageClassResources{
+			Name:        class.name,
+			Provisioner: class.provisioner,
+			Allocated:   class.allocated,
+		}
+		if class.tracked {
+			available := class.available
+			capacity := class.available.DeepCopy()
+			capacity.Add(class.allocated)
+			resources.Available = &available
+			resources.Capacity = &capacity
+		}
+		result = append(result, resources)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// ErrUsageMetricsUnavailable is returned when the cluster does not serve the metrics.k8s.io API
+var ErrUsageMetricsUnavailable = errors.New("metrics.k8s.io API is not available")
+
+// nodeMetricsListGVK identifies the NodeMetricsList kind served by metrics-server
+var nodeMetricsListGVK = schema.GroupVersionKind{
+	Group:   "metrics.k8s.io",
+	Version: "v1beta1",
+	Kind:    "NodeMetricsList",
+}
+
+// UsageSource reports the resources actually consumed on each node
+type UsageSource interface {
+	// NodeUsage returns the current usage keyed by node name
+	NodeUsage(ctx context.Context) (map[string]corev1.ResourceList, error)
+}
+
+// MetricsServerUsageSource reads NodeMetrics from the metrics.k8s.io API.
+// Objects are read as unstructured so the agent does not depend on the metrics client library.
+//
+// PodMetrics are deliberately not read. Used is only advertised as a total over the
+// advertised nodes, and the usage of a node already covers every pod running on it,
+// plus the system daemons and kubelet overhead that no pod accounts for. Summing
+// PodMetrics would list every pod of the cluster on each reconcile and still miss that
+// overhead. Availability is derived from requests, never from usage, so nothing needs
+// the usage of individual pods.
+type MetricsServerUsageSource struct {
+	// Reader should bypass the informer cache: metrics.k8s.io does not support watch
+	Reader client.Reader
+}
+
+// NodeUsage lists NodeMetrics and returns their usage keyed by node name
+func (s *MetricsServerUsageSource) NodeUsage(ctx context.Context) (map[string]corev1.ResourceList, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(nodeMetricsListGVK)
+
+	if err := s.Reader.List(ctx, list); err != nil {
+		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err) {
+			return nil, fmt.Errorf("%w: %v", ErrUsageMetricsUnavailable, err)
+		}
+		return nil, fmt.Errorf("failed to list node metrics: %w", err)
+	}
+
+	usage := make(map[string]corev1.ResourceList, len(list.Items))
+	for _, item := range list.Items {
+		values, found, err := unstructured.NestedStringMap(item.Object, "usage")
+		if err != nil || !found {
+			continue
+		}
+
+		nodeUsage := corev1.ResourceList{}
+		for name, value := range values {
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				continue
+			}
+			nodeUsage[corev1.ResourceName(name)] = quantity
+		}
+		usage[item.GetName()] = nodeUsage
+	}
+
+	return usage, nil
+}
+
+// CollectUsage sums the usage reported by the UsageSource over the advertised nodes.
+// It returns nil without error when no UsageSource is configured.
+func (c *Collector) CollectUsage(ctx context.Context, snapshot *Snapshot) (*rearv1alpha1.ResourceQuantities, error) {
+	if c.UsageSource == nil {
+		return nil, nil
+	}
+	logger := log.FromContext(ctx).WithName("metrics-collector")
+
+	usage, err := c.UsageSource.NodeUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	used := corev1.ResourceList{}
+	missing := 0
+	for _, node := range snapshot.nodes {
+		nodeUsage, ok := usage[node.name]
+		if !ok {
+			missing++
+			continue
+		}
+		c.addResources(used, nodeUsage)
+	}
+
+	if missing > 0 {
+		logger.Info("usage metrics missing for some advertised nodes", "nodes", missing)
+	}
+
+	quantities := c.toResourceQuantities(used)
+	return &quantities, nil
+}
@@ -0,0 +1,127 @@
+package policy
+
+import (
+	"testing"
+	"time"
+)
+
+// bitset builds the bitset of a list of values
+func bitset(values ...int) uint64 {
+	var bits uint64
+	for _, value := range values {
+		bits |= 1 << value
+	}
+	return bits
+}
+
+func TestParseCronField(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		low     int
+		high    int
+		want    uint64
+		wantErr bool
+	}{
+		{name: "wildcard", field: "*", low: 0, high: 6, want: bitset(0, 1, 2, 3, 4, 5, 6)},
+		{name: "single value", field: "5", low: 0, high: 59, want: bitset(5)},
+		{name: "list", field: "1,15,30", low: 0, high: 59, want: bitset(1, 15, 30)},
+		{name: "range", field: "9-12", low: 0, high: 23, want: bitset(9, 10, 11, 12)},
+		{name: "wildcard step", field: "*/15", low: 0, high: 59, want: bitset(0, 15, 30, 45)},
+		{name: "range step", field: "0-30/10", low: 0, high: 59, want: bitset(0, 10, 20, 30)},
+		{name: "value step runs to the end", field: "5/20", low: 0, high: 59, want: bitset(5, 25, 45)},
+		{name: "list of ranges and steps", field: "1-3,10,20-30/5", low: 0, high: 59, want: bitset(1, 2, 3, 10, 20, 25, 30)},
+		{name: "wildcard step from a non-zero low", field: "*/2", low: 1, high: 12, want: bitset(1, 3, 5, 7, 9, 11)},
+		{name: "value below the range", field: "0", low: 1, high: 31, wantErr: true},
+		{name: "value above the range", field: "60", low: 0, high: 59, wantErr: true},
+		{name: "reversed range", field: "10-5", low: 0, high: 59, wantErr: true},
+		{name: "zero step", field: "*/0", low: 0, high: 59, wantErr: true},
+		{name: "negative step", field: "*/-5", low: 0, high: 59, wantErr: true},
+		{name: "not a number", field: "mon", low: 0, high: 7, wantErr: true},
+		{name: "empty list entry", field: "1,,2", low: 0, high: 59, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCronField(tt.field, tt.low, tt.high)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseCronField(%q) = %b, want an error", tt.field, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCronField(%q): %v", tt.field, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseCronField(%q) = %b, want %b", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"0 9 * *",
+		"0 9 * * * *",
+		"0 24 * * *",
+		"0 9 32 * *",
+		"0 9 * 13 *",
+		"0 9 * * 8",
+	} {
+		if _, err := parseCron(expression); err == nil {
+			t.Errorf("parseCron(%q) succeeded, want an error", expression)
+		}
+	}
+}
+
+func TestCronMatches(t *testing.T) {
+	// 2026-03-13 is a Friday and 2026-03-15 a Sunday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.March, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		at         time.Time
+		want       bool
+	}{
+		{name: "every minute", expression: "* * * * *", at: at(12, 3, 17), want: true},
+		{name: "minute and hour match", expression: "30 9 * * *", at: at(12, 9, 30), want: true},
+		{name: "minute does not match", expression: "30 9 * * *", at: at(12, 9, 31), want: false},
+		{name: "hour does not match", expression: "30 9 * * *", at: at(12, 10, 30), want: false},
+		{name: "hour range", expression: "0 9-17 * * *", at: at(12, 17, 0), want: true},
+		{name: "minute step", expression: "*/15 * * * *", at: at(12, 4, 45), want: true},
+		{name: "minute step miss", expression: "*/15 * * * *", at: at(12, 4, 50), want: false},
+		{name: "month list", expression: "0 0 * 1,3 *", at: at(12, 0, 0), want: true},
+		{name: "month miss", expression: "0 0 * 4-12 *", at: at(12, 0, 0), want: false},
+		{name: "weekday range", expression: "0 9 * * 1-5", at: at(13, 9, 0), want: true},
+		{name: "weekend outside a weekday range", expression: "0 9 * * 1-5", at: at(15, 9, 0), want: false},
+		{name: "Sunday as 0", expression: "0 9 * * 0", at: at(15, 9, 0), want: true},
+		{name: "Sunday as 7", expression: "0 9 * * 7", at: at(15, 9, 0), want: true},
+		{name: "range ending on Sunday as 7", expression: "0 9 * * 5-7", at: at(15, 9, 0), want: true},
+		{name: "day of month only", expression: "0 0 13 * *", at: at(13, 0, 0), want: true},
+		{name: "day of month only, other day", expression: "0 0 13 * *", at: at(20, 0, 0), want: false},
+		{name: "day of week only", expression: "0 0 * * 5", at: at(20, 0, 0), want: true},
+		{name: "day of week only, other day", expression: "0 0 * * 5", at: at(19, 0, 0), want: false},
+		{name: "both days restricted, day of month matches", expression: "0 0 12 * 5", at: at(12, 0, 0), want: true},
+		{name: "both days restricted, day of week matches", expression: "0 0 12 * 5", at: at(20, 0, 0), want: true},
+		{name: "both days restricted, neither matches", expression: "0 0 12 * 5", at: at(19, 0, 0), want: false},
+		{name: "both days restricted, time still applies", expression: "0 0 12 * 5", at: at(20, 1, 0), want: false},
+		{name: "stepped day of month counts as restricted", expression: "0 0 */10 * 5", at: at(20, 0, 0), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := parseCron(tt.expression)
+			if err != nil {
+				t.Fatalf("parseCron(%q): %v", tt.expression, err)
+			}
+			if got := cron.matches(tt.at); got != tt.want {
+				t.Errorf("%q matches %s = %t, want %t", tt.expression, tt.at.Format(time.RFC1123), got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package policy applies the lending rules of an AdvertisementPolicy to the
+// resources the cluster has free, so that the advertised availability only
+// covers what the cluster is actually willing to lend.
+package policy
@@ -0,0 +1,121 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/inf.v0"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// Apply adjusts available (allocatable - allocated - reserved) in place according to spec.
+// For each listed resource, in order:
+//   - overcommit adds the extra share of allocatable granted by OvercommitPercent
+//   - headroom is subtracted
+//   - the result is bounded by Cap minus what provider instructions already reserve
+//   - negative amounts are clamped to zero
+//
+// Resources that available does not track are ignored. A nil spec leaves available untouched.
+func Apply(spec *rearv1alpha1.AdvertisementPolicySpec, allocatable, reserved, available corev1.ResourceList) error {
+	if spec == nil {
+		return nil
+	}
+	return applyRules(spec.Resources, allocatable, reserved, available)
+}
+
+// ApplySchedule adjusts available in place according to the state of a lending schedule,
+// after Apply: the rules of the window in effect apply in the same way, and every resource
+// drops to zero when nothing is lent. A nil state leaves available untouched.
+func ApplySchedule(state *ScheduleState, allocatable, reserved, available corev1.ResourceList) error {
+	if state == nil {
+		return nil
+	}
+
+	if !state.Lending {
+		for name, quantity := range available {
+			available[name] = *resource.NewQuantity(0, quantity.Format)
+		}
+		return nil
+	}
+	return applyRules(state.Rules, allocatable, reserved, available)
+}
+
+// applyRules applies a list of resource rules to available
+func applyRules(rules []rearv1alpha1.ResourcePolicy, allocatable, reserved, available corev1.ResourceList) error {
+	for _, rule := range rules {
+		name := corev1.ResourceName(rule.Name)
+		free, ok := available[name]
+		if !ok {
+			continue
+		}
+		base := allocatable[name]
+
+		if rule.OvercommitPercent != nil && *rule.OvercommitPercent != 100 {
+			free.Add(scale(base, int64(*rule.OvercommitPercent)-100))
+		}
+
+		if rule.Headroom != "" {
+			headroom, err := parseAmount(rule.Headroom, base)
+			if err != nil {
+				return fmt.Errorf("invalid headroom for %s: %w", rule.Name, err)
+			}
+			free.Sub(headroom)
+		}
+
+		if rule.Cap != "" {
+			limit, err := parseAmount(rule.Cap, base)
+			if err != nil {
+				return fmt.Errorf("invalid cap for %s: %w", rule.Name, err)
+			}
+			limit.Sub(reserved[name])
+			if free.Cmp(limit) > 0 {
+				free = limit
+			}
+		}
+
+		if free.Sign() < 0 {
+			free = *resource.NewQuantity(0, base.Format)
+		}
+		available[name] = free
+	}
+
+	return nil
+}
+
+// parseAmount parses an absolute quantity or a percentage of base
+func parseAmount(value string, base resource.Quantity) (resource.Quantity, error) {
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		p, err := strconv.ParseInt(strings.TrimSpace(percent), 10, 64)
+		if err != nil || p < 0 {
+			return resource.Quantity{}, fmt.Errorf("invalid percentage %q", value)
+		}
+		return scale(base, p), nil
+	}
+
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("invalid quantity %q: %w", value, err)
+	}
+	return quantity, nil
+}
+
+// scale returns percent% of q, truncated to milli-units and keeping whole units when
+// possible so memory stays readable. The arithmetic uses arbitrary-precision decimals:
+// the milli-units of a large memory amount times a percentage overflow an int64
+// (100Ti at 100% already does).
+func scale(q resource.Quantity, percent int64) resource.Quantity {
+	scaled := new(inf.Dec).Mul(q.AsDec(), inf.NewDec(percent, 0))
+	scaled.QuoRound(scaled, inf.NewDec(100, 0), 3, inf.RoundDown)
+
+	whole := new(inf.Dec).Round(scaled, 0, inf.RoundDown)
+	if whole.Cmp(scaled) == 0 {
+		if value, ok := whole.Unscaled(); ok {
+			return *resource.NewQuantity(value, q.Format)
+		}
+	}
+	return *resource.NewDecimalQuantity(*scaled, q.Format)
+}
@@ -0,0 +1,101 @@
+package policy
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity string
+		percent  int64
+		want     string
+	}{
+		{name: "whole cores", quantity: "8", percent: 50, want: "4"},
+		{name: "fractional cores", quantity: "3", percent: 10, want: "300m"},
+		{name: "truncated to milli-units", quantity: "1", percent: 33, want: "330m"},
+		{name: "negative share", quantity: "10", percent: -20, want: "-2"},
+		{name: "gibibytes", quantity: "64Gi", percent: 25, want: "16Gi"},
+		{name: "100Ti at 100%", quantity: "100Ti", percent: 100, want: "100Ti"},
+		{name: "100Ti overcommitted at 150%", quantity: "100Ti", percent: 150, want: "150Ti"},
+		{name: "1Pi at 10%", quantity: "1Pi", percent: 10, want: "112589990684262400m"},
+		{name: "8Pi at 200%", quantity: "8Pi", percent: 200, want: "16Pi"},
+		{name: "16Ei at 100%", quantity: "16Ei", percent: 100, want: "16Ei"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scale(resource.MustParse(tt.quantity), tt.percent)
+			want := resource.MustParse(tt.want)
+			if got.Cmp(want) != 0 {
+				t.Errorf("scale(%s, %d) = %s, want %s", tt.quantity, tt.percent, got.String(), want.String())
+			}
+			if got.Sign() != want.Sign() {
+				t.Errorf("scale(%s, %d) has sign %d, want %d", tt.quantity, tt.percent, got.Sign(), want.Sign())
+			}
+		})
+	}
+}
+
+func TestApplyLargeCluster(t *testing.T) {
+	int32Ptr := func(v int32) *int32 { return &v }
+
+	tests := []struct {
+		name        string
+		rule        rearv1alpha1.ResourcePolicy
+		allocatable string
+		available   string
+		want        string
+	}{
+		{
+			name:        "overcommit",
+			rule:        rearv1alpha1.ResourcePolicy{Name: "memory", OvercommitPercent: int32Ptr(150)},
+			allocatable: "100Ti",
+			available:   "40Ti",
+			want:        "90Ti",
+		},
+		{
+			name:        "percentage headroom",
+			rule:        rearv1alpha1.ResourcePolicy{Name: "memory", Headroom: "10%"},
+			allocatable: "200Ti",
+			available:   "100Ti",
+			want:        "80Ti",
+		},
+		{
+			name:        "percentage cap",
+			rule:        rearv1alpha1.ResourcePolicy{Name: "memory", Cap: "50%"},
+			allocatable: "2Pi",
+			available:   "1536Ti",
+			want:        "1Pi",
+		},
+		{
+			name:        "full cap leaves availability untouched",
+			rule:        rearv1alpha1.ResourcePolicy{Name: "memory", Cap: "100%"},
+			allocatable: "100Ti",
+			available:   "60Ti",
+			want:        "60Ti",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &rearv1alpha1.AdvertisementPolicySpec{Resources: []rearv1alpha1.ResourcePolicy{tt.rule}}
+			allocatable := corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(tt.allocatable)}
+			available := corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(tt.available)}
+
+			if err := Apply(spec, allocatable, corev1.ResourceList{}, available); err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+
+			got := available[corev1.ResourceMemory]
+			if want := resource.MustParse(tt.want); got.Cmp(want) != 0 {
+				t.Errorf("available = %s, want %s", got.String(), want.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// maxWindowDuration bounds the length of a lending window occurrence
+const maxWindowDuration = 7 * 24 * time.Hour
+
+// transitionLookahead is how far ahead the next schedule transition is searched
+const transitionLookahead = 7 * 24 * time.Hour
+
+// ScheduleState is the outcome of a lending schedule at a point in time
+type ScheduleState struct {
+	// Lending is false when no window is open and no OutsideWindows rules are set
+	Lending bool
+	// Window is the name of the open window, empty outside every window
+	Window string
+	// Rules are the resource rules in effect, applied on top of the policy Resources
+	Rules []rearv1alpha1.ResourcePolicy
+	// NextTransition is when the window in effect changes, nil beyond the lookahead
+	NextTransition *time.Time
+}
+
+// interval is an occurrence of a window, or the union of overlapping ones
+type interval struct {
+	start, end time.Time
+}
+
+// EvaluateSchedule returns the state of the lending schedule of spec at now,
+// or nil when spec has no schedule
+func EvaluateSchedule(spec *rearv1alpha1.AdvertisementPolicySpec, now time.Time) (*ScheduleState, error) {
+	if spec == nil || spec.Schedule == nil {
+		return nil, nil
+	}
+	schedule := spec.Schedule
+
+	location := time.UTC
+	if schedule.TimeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(schedule.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", schedule.TimeZone, err)
+		}
+	}
+	now = now.In(location)
+
+	occurrences := make([][]interval, len(schedule.Windows))
+	for i, window := range schedule.Windows {
+		cron, err := parseCron(window.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule for window %s: %w", window.Name, err)
+		}
+		duration := window.Duration.Duration
+		if duration <= 0 || duration > maxWindowDuration {
+			return nil, fmt.Errorf("duration of window %s must be between 0 and %s", window.Name, maxWindowDuration)
+		}
+		occurrences[i] = windowOccurrences(cron, duration, now.Add(-duration), now.Add(transitionLookahead))
+	}
+
+	current := activeWindow(occurrences, now)
+	state := &ScheduleState{}
+	switch {
+	case current >= 0:
+		state.Lending = true
+		state.Window = schedule.Windows[current].Name
+		state.Rules = schedule.Windows[current].Resources
+	case len(schedule.OutsideWindows) > 0:
+		state.Lending = true
+		state.Rules = schedule.OutsideWindows
+	}
+
+	// The window in effect can only change where an occurrence starts or ends
+	var boundaries []time.Time
+	for _, window := range occurrences {
+		for _, occurrence := range window {
+			boundaries = append(boundaries, occurrence.start, occurrence.end)
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].Before(boundaries[j]) })
+	for _, boundary := range boundaries {
+		if !boundary.After(now) || boundary.After(now.Add(transitionLookahead)) {
+			continue
+		}
+		if activeWindow(occurrences, boundary) != current {
+			next := boundary
+			state.NextTransition = &next
+			break
+		}
+	}
+
+	return state, nil
+}
+
+// windowOccurrences lists the merged occurrences of a window starting between from and to
+func windowOccurrences(cron *cronSchedule, duration time.Duration, from, to time.Time) []interval {
+	var occurrences []interval
+	for start := from.Truncate(time.Minute); !start.After(to); start = start.Add(time.Minute) {
+		if !cron.matches(start) {
+			continue
+		}
+		end := start.Add(duration)
+		if last := len(occurrences) - 1; last >= 0 && !start.After(occurrences[last].end) {
+			occurrences[last].end = end
+			continue
+		}
+		occurrences = append(occurrences, interval{start: start, end: end})
+	}
+	return occurrences
+}
+
+// activeWindow returns the index of the first window with an occurrence containing t, or -1
+func activeWindow(occurrences [][]interval, t time.Time) int {
+	for i, window := range occurrences {
+		j := sort.Search(len(window), func(k int) bool { return window[k].end.After(t) })
+		if j < len(window) && !window[j].start.After(t) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,261 @@
+package policy
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // The DST cases must not depend on the zoneinfo of the host
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// window builds a lending window
+func window(name, schedule string, duration time.Duration) rearv1alpha1.LendingWindow {
+	return rearv1alpha1.LendingWindow{Name: name, Schedule: schedule, Duration: metav1.Duration{Duration: duration}}
+}
+
+func TestEvaluateSchedule(t *testing.T) {
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	outside := []rearv1alpha1.ResourcePolicy{{Name: "cpu", Headroom: "50%"}}
+
+	tests := []struct {
+		name     string
+		schedule rearv1alpha1.LendingSchedule
+		now      time.Time
+
+		wantLending bool
+		wantWindow  string
+		wantRules   bool
+		// wantNext is nil when no transition is expected within the lookahead
+		wantNext *time.Time
+	}{
+		{
+			name:        "inside a window",
+			schedule:    rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{window("office", "0 9 * * *", 8*time.Hour)}},
+			now:         utc(time.March, 12, 10, 0),
+			wantLending: true,
+			wantWindow:  "office",
+			wantNext:    ptr(utc(time.March, 12, 17, 0)),
+		},
+		{
+			name:     "outside every window without outside rules lends nothing",
+			schedule: rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{window("office", "0 9 * * *", 8*time.Hour)}},
+			now:      utc(time.March, 12, 18, 0),
+			wantNext: ptr(utc(time.March, 13, 9, 0)),
+		},
+		{
+			name: "outside every window the outside rules apply",
+			schedule: rearv1alpha1.LendingSchedule{
+				Windows:        []rearv1alpha1.LendingWindow{window("office", "0 9 * * *", 8*time.Hour)},
+				OutsideWindows: outside,
+			},
+			now:         utc(time.March, 12, 18, 0),
+			wantLending: true,
+			wantRules:   true,
+			wantNext:    ptr(utc(time.March, 13, 9, 0)),
+		},
+		{
+			name:        "window end is exclusive",
+			schedule:    rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{window("office", "0 9 * * *", 8*time.Hour)}},
+			now:         utc(time.March, 12, 17, 0),
+			wantLending: false,
+			wantNext:    ptr(utc(time.March, 13, 9, 0)),
+		},
+		{
+			name:        "window crossing midnight, before midnight",
+			schedule:    rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{window("night", "0 22 * * *", 6*time.Hour)}},
+			now:         utc(time.March, 12, 23, 30),
+			wantLending: true,
+			wantWindow:  "night",
+			wantNext:    ptr(utc(time.March, 13, 4, 0)),
+		},
+		{
+			name:        "window crossing midnight, after midnight",
+			schedule:    rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{window("night", "0 22 * * *", 6*time.Hour)}},
+			now:         utc(time.March, 13, 3, 0),
+			wantLending: true,
+			wantWindow:  "night",
+			wantNext:    ptr(utc(time.March, 13, 4, 0)),
+		},
+		{
+			name: "weekday window crossing midnight into the weekend",
+			// Friday 2026-03-13 at 22:00 until Saturday 04:00
+			schedule:    rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{window("night", "0 22 * * 1-5", 6*time.Hour)}},
+			now:         utc(time.March, 14, 2, 0),
+			wantLending: true,
+			wantWindow:  "night",
+			wantNext:    ptr(utc(time.March, 14, 4, 0)),
+		},
+		{
+			name: "overlapping windows, the first listed wins",
+			schedule: rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{
+				window("morning", "0 8 * * *", 4*time.Hour),
+				window("midday", "0 10 * * *", 4*time.Hour),
+			}},
+			now:         utc(time.March, 12, 11, 0),
+			wantLending: true,
+			wantWindow:  "morning",
+			// The morning window ends while the midday one is still open
+			wantNext: ptr(utc(time.March, 12, 12, 0)),
+		},
+		{
+			name: "overlapping windows, once the first one ends",
+			schedule: rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{
+				window("morning", "0 8 * * *", 4*time.Hour),
+				window("midday", "0 10 * * *", 4*time.Hour),
+			}},
+			now:         utc(time.March, 12, 12, 30),
+			wantLending: true,
+			wantWindow:  "midday",
+			wantNext:    ptr(utc(time.March, 12, 14, 0)),
+		},
+		{
+			name: "overlapping occurrences of a window merge into one",
+			// Every hour for 90 minutes: the window never closes
+			schedule:    rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{window("always", "0 * * * *", 90*time.Minute)}},
+			now:         utc(time.March, 12, 10, 45),
+			wantLending: true,
+			wantWindow:  "always",
+		},
+		{
+			name: "time zone shifts the window",
+			schedule: rearv1alpha1.LendingSchedule{
+				TimeZone: "Europe/Rome",
+				Windows:  []rearv1alpha1.LendingWindow{window("office", "0 9 * * *", 8*time.Hour)},
+			},
+			// 09:30 in Rome (UTC+1)
+			now:         utc(time.March, 12, 8, 30),
+			wantLending: true,
+			wantWindow:  "office",
+			wantNext:    ptr(utc(time.March, 12, 16, 0)),
+		},
+		{
+			name: "spring DST change moves the next start by an hour in UTC",
+			schedule: rearv1alpha1.LendingSchedule{
+				TimeZone: "Europe/Rome",
+				Windows:  []rearv1alpha1.LendingWindow{window("office", "0 9 * * *", 8*time.Hour)},
+			},
+			// 2026-03-29 is the first day of CEST (UTC+2): 09:00 local is 07:00 UTC
+			now:      utc(time.March, 28, 20, 0),
+			wantNext: ptr(utc(time.March, 29, 7, 0)),
+		},
+		{
+			name: "spring DST change skips a start in the missing hour",
+			schedule: rearv1alpha1.LendingSchedule{
+				TimeZone: "Europe/Rome",
+				Windows:  []rearv1alpha1.LendingWindow{window("maintenance", "30 2 * * *", time.Hour)},
+			},
+			// 02:30 does not exist on 2026-03-29; the next start is on 2026-03-30 at 00:30 UTC
+			now:      utc(time.March, 29, 0, 0),
+			wantNext: ptr(utc(time.March, 30, 0, 30)),
+		},
+		{
+			name: "autumn DST change repeats a start in the doubled hour",
+			schedule: rearv1alpha1.LendingSchedule{
+				TimeZone: "Europe/Rome",
+				Windows:  []rearv1alpha1.LendingWindow{window("maintenance", "30 2 * * *", time.Hour)},
+			},
+			// On 2026-10-25 02:30 local happens at 00:30 and again at 01:30 UTC,
+			// so the two occurrences merge into one lasting until 02:30 UTC
+			now:         utc(time.October, 25, 1, 15),
+			wantLending: true,
+			wantWindow:  "maintenance",
+			wantNext:    ptr(utc(time.October, 25, 2, 30)),
+		},
+		{
+			name:     "next transition just beyond the lookahead",
+			schedule: rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{window("monthly", "0 9 1 * *", time.Hour)}},
+			now:      utc(time.March, 25, 8, 59),
+		},
+		{
+			name:     "next transition exactly at the lookahead",
+			schedule: rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{window("monthly", "0 9 1 * *", time.Hour)}},
+			now:      utc(time.March, 25, 9, 0),
+			wantNext: ptr(utc(time.April, 1, 9, 0)),
+		},
+		{
+			name:     "weekly window found almost a week ahead",
+			schedule: rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{window("weekly", "0 9 * * 1", time.Hour)}},
+			// Monday 2026-03-16, right after the window closed
+			now:      utc(time.March, 16, 10, 0),
+			wantNext: ptr(utc(time.March, 23, 9, 0)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := tt.schedule
+			state, err := EvaluateSchedule(&rearv1alpha1.AdvertisementPolicySpec{Schedule: &schedule}, tt.now)
+			if err != nil {
+				t.Fatalf("EvaluateSchedule: %v", err)
+			}
+
+			if state.Lending != tt.wantLending {
+				t.Errorf("Lending = %t, want %t", state.Lending, tt.wantLending)
+			}
+			if state.Window != tt.wantWindow {
+				t.Errorf("Window = %q, want %q", state.Window, tt.wantWindow)
+			}
+			if gotRules := len(state.Rules) > 0; gotRules != tt.wantRules {
+				t.Errorf("Rules = %v, want rules: %t", state.Rules, tt.wantRules)
+			}
+			switch {
+			case tt.wantNext == nil && state.NextTransition != nil:
+				t.Errorf("NextTransition = %s, want none", state.NextTransition.UTC())
+			case tt.wantNext != nil && state.NextTransition == nil:
+				t.Errorf("NextTransition = none, want %s", tt.wantNext)
+			case tt.wantNext != nil && !state.NextTransition.Equal(*tt.wantNext):
+				t.Errorf("NextTransition = %s, want %s", state.NextTransition.UTC(), tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestEvaluateScheduleInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule rearv1alpha1.LendingSchedule
+	}{
+		{
+			name:     "unknown time zone",
+			schedule: rearv1alpha1.LendingSchedule{TimeZone: "Mars/Olympus", Windows: []rearv1alpha1.LendingWindow{window("w", "0 9 * * *", time.Hour)}},
+		},
+		{
+			name:     "invalid cron expression",
+			schedule: rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{window("w", "0 9 * *", time.Hour)}},
+		},
+		{
+			name:     "zero duration",
+			schedule: rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{window("w", "0 9 * * *", 0)}},
+		},
+		{
+			name:     "duration above a week",
+			schedule: rearv1alpha1.LendingSchedule{Windows: []rearv1alpha1.LendingWindow{window("w", "0 9 * * *", 8*24*time.Hour)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := tt.schedule
+			if _, err := EvaluateSchedule(&rearv1alpha1.AdvertisementPolicySpec{Schedule: &schedule}, time.Now()); err == nil {
+				t.Errorf("EvaluateSchedule succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestEvaluateScheduleWithoutSchedule(t *testing.T) {
+	for _, spec := range []*rearv1alpha1.AdvertisementPolicySpec{nil, {}} {
+		state, err := EvaluateSchedule(spec, time.Now())
+		if err != nil || state != nil {
+			t.Errorf("EvaluateSchedule(%v) = %v, %v, want nil, nil", spec, state, err)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,3 @@
+// Package pricing turns a PricingPolicy into the cost information advertised
+// to the broker, so that it can match requests to clusters by price.
+package pricing
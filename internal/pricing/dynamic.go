@@ -0,0 +1,287 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// Priced resources, as named in PricingStatus
+const (
+	resourceCPU     = "cpu"
+	resourceMemory  = "memory"
+	resourceGPU     = "gpu"
+	resourceStorage = "storage"
+)
+
+// pricedResources lists the resources with a price, in the order they are reported
+var pricedResources = []string{resourceCPU, resourceMemory, resourceGPU, resourceStorage}
+
+// curvePoint is a parsed point of the utilization curve
+type curvePoint struct {
+	utilization float64
+	multiplier  float64
+}
+
+// adjust scales the prices of cost in place and returns the record of the computation.
+// Every price of a resource, cluster-wide or per pool, gets the same multiplier, derived
+// from the cluster-wide utilization of that resource, so pool prices keep their ratios.
+func adjust(
+	cost *rearv1alpha1.CostInfo,
+	dynamic *rearv1alpha1.DynamicPricing,
+	resources *rearv1alpha1.ResourceMetrics,
+	now time.Time,
+) (*rearv1alpha1.PricingStatus, error) {
+	if err := validateBounds(dynamic.Floor, dynamic.Ceiling); err != nil {
+		return nil, err
+	}
+
+	curve, err := parseCurve(dynamic.UtilizationCurve)
+	if err != nil {
+		return nil, err
+	}
+
+	timeMultiplier, err := timeOfDayMultiplier(dynamic.TimeOfDay, dynamic.TimeZone, now)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &rearv1alpha1.PricingStatus{
+		ComputedAt:          metav1.NewTime(now),
+		TimeOfDayMultiplier: formatPrice(timeMultiplier),
+	}
+
+	for _, name := range pricedResources {
+		price := costField(cost, name)
+		if *price == "" {
+			continue
+		}
+
+		utilization := utilizationPercent(resources, name)
+		utilizationMultiplier := curveMultiplier(curve, utilization)
+		multiplier := utilizationMultiplier * timeMultiplier
+
+		record := rearv1alpha1.ResourcePricing{
+			Resource:              name,
+			BasePrice:             *price,
+			Utilization:           int32(math.Round(utilization)),
+			UtilizationMultiplier: formatPrice(utilizationMultiplier),
+		}
+		if *price, record.Bound, err = scalePrice(*price, multiplier, dynamic, name); err != nil {
+			return nil, err
+		}
+		record.Price = *price
+		status.Resources = append(status.Resources, record)
+
+		for i := range cost.NodePools {
+			poolPrice := poolCostField(&cost.NodePools[i], name)
+			if *poolPrice == "" {
+				continue
+			}
+			if *poolPrice, _, err = scalePrice(*poolPrice, multiplier, dynamic, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return status, nil
+}
+
+// scalePrice multiplies a price and clamps it to the floor and ceiling of the resource,
+// returning which bound applied, if any
+func scalePrice(price string, multiplier float64, dynamic *rearv1alpha1.DynamicPricing, name string) (string, string, error) {
+	value, err := parsePrice(price)
+	if err != nil {
+		return "", "", err
+	}
+	value *= multiplier
+
+	bound := ""
+	if floor := boundField(dynamic.Floor, name); floor != "" {
+		if limit, _ := parsePrice(floor); value < limit {
+			value, bound = limit, rearv1alpha1.PriceBoundFloor
+		}
+	}
+	if ceiling := boundField(dynamic.Ceiling, name); ceiling != "" {
+		if limit, _ := parsePrice(ceiling); value > limit {
+			value, bound = limit, rearv1alpha1.PriceBoundCeiling
+		}
+	}
+	return formatPrice(value), bound, nil
+}
+
+// validateBounds checks the floor and ceiling prices and that no floor exceeds its ceiling
+func validateBounds(floor, ceiling rearv1alpha1.ResourcePrices) error {
+	if err := validatePrices(floor); err != nil {
+		return fmt.Errorf("invalid floor prices: %w", err)
+	}
+	if err := validatePrices(ceiling); err != nil {
+		return fmt.Errorf("invalid ceiling prices: %w", err)
+	}
+
+	for _, name := range pricedResources {
+		low, high := boundField(floor, name), boundField(ceiling, name)
+		if low == "" || high == "" {
+			continue
+		}
+		lowValue, _ := parsePrice(low)
+		highValue, _ := parsePrice(high)
+		if lowValue > highValue {
+			return fmt.Errorf("%s floor %s exceeds ceiling %s", name, low, high)
+		}
+	}
+	return nil
+}
+
+// parseCurve parses the utilization curve and sorts it by utilization
+func parseCurve(points []rearv1alpha1.UtilizationPoint) ([]curvePoint, error) {
+	curve := make([]curvePoint, 0, len(points))
+	for _, point := range points {
+		multiplier, err := parsePrice(point.Multiplier)
+		if err != nil {
+			return nil, fmt.Errorf("invalid multiplier at %d%% utilization: %w", point.Utilization, err)
+		}
+		curve = append(curve, curvePoint{utilization: float64(point.Utilization), multiplier: multiplier})
+	}
+	sort.Slice(curve, func(i, j int) bool { return curve[i].utilization < curve[j].utilization })
+	return curve, nil
+}
+
+// curveMultiplier interpolates the multiplier of the curve at the given utilization.
+// An empty curve leaves prices unchanged.
+func curveMultiplier(curve []curvePoint, utilization float64) float64 {
+	if len(curve) == 0 {
+		return 1
+	}
+	if utilization <= curve[0].utilization {
+		return curve[0].multiplier
+	}
+	for i := 1; i < len(curve); i++ {
+		low, high := curve[i-1], curve[i]
+		if utilization <= high.utilization {
+			fraction := (utilization - low.utilization) / (high.utilization - low.utilization)
+			return low.multiplier + fraction*(high.multiplier-low.multiplier)
+		}
+	}
+	return curve[len(curve)-1].multiplier
+}
+
+// timeOfDayMultiplier returns the multiplier of the first window containing now, or 1
+func timeOfDayMultiplier(windows []rearv1alpha1.TimeOfDayMultiplier, timeZone string, now time.Time) (float64, error) {
+	location := time.UTC
+	if timeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(timeZone); err != nil {
+			return 0, fmt.Errorf("invalid time zone %q: %w", timeZone, err)
+		}
+	}
+
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+
+	for _, window := range windows {
+		start, err := minuteOfDay(window.Start)
+		if err != nil {
+			return 0, err
+		}
+		end, err := minuteOfDay(window.End)
+		if err != nil {
+			return 0, err
+		}
+		multiplier, err := parsePrice(window.Multiplier)
+		if err != nil {
+			return 0, fmt.Errorf("invalid multiplier for window %s-%s: %w", window.Start, window.End, err)
+		}
+
+		inWindow := minute >= start && minute < end
+		if end <= start {
+			inWindow = minute >= start || minute < end
+		}
+		if inWindow {
+			return multiplier, nil
+		}
+	}
+	return 1, nil
+}
+
+// minuteOfDay parses an HH:MM time into minutes since midnight
+func minuteOfDay(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// utilizationPercent returns the share of the allocatable amount of a resource allocated to pods
+func utilizationPercent(resources *rearv1alpha1.ResourceMetrics, name string) float64 {
+	if resources == nil {
+		return 0
+	}
+
+	var allocatable, allocated *resource.Quantity
+	switch name {
+	case resourceCPU:
+		allocatable, allocated = &resources.Allocatable.CPU, &resources.Allocated.CPU
+	case resourceMemory:
+		allocatable, allocated = &resources.Allocatable.Memory, &resources.Allocated.Memory
+	case resourceGPU:
+		allocatable, allocated = resources.Allocatable.GPU, resources.Allocated.GPU
+	case resourceStorage:
+		allocatable, allocated = resources.Allocatable.Storage, resources.Allocated.Storage
+	}
+	if allocatable == nil || allocated == nil || allocatable.Sign() <= 0 {
+		return 0
+	}
+
+	percent := 100 * allocated.AsApproximateFloat64() / allocatable.AsApproximateFloat64()
+	return math.Max(0, math.Min(100, percent))
+}
+
+// costField returns the cluster-wide price of a resource
+func costField(cost *rearv1alpha1.CostInfo, name string) *string {
+	switch name {
+	case resourceCPU:
+		return &cost.CPUCost
+	case resourceMemory:
+		return &cost.MemoryCost
+	case resourceGPU:
+		return &cost.GPUCost
+	default:
+		return &cost.StorageCost
+	}
+}
+
+// poolCostField returns the price of a resource in a node pool
+func poolCostField(pool *rearv1alpha1.NodePoolCost, name string) *string {
+	switch name {
+	case resourceCPU:
+		return &pool.CPUCost
+	case resourceMemory:
+		return &pool.MemoryCost
+	case resourceGPU:
+		return &pool.GPUCost
+	default:
+		return &pool.StorageCost
+	}
+}
+
+// boundField returns the floor or ceiling price of a resource
+func boundField(prices rearv1alpha1.ResourcePrices, name string) string {
+	switch name {
+	case resourceCPU:
+		return prices.CPU
+	case resourceMemory:
+		return prices.Memory
+	case resourceGPU:
+		return prices.GPU
+	default:
+		return prices.Storage
+	}
+}
@@ -0,0 +1,279 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+	"time"
+	_ "time/tzdata" // The time zone cases must not depend on the zoneinfo of the host
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+func TestCurveMultiplier(t *testing.T) {
+	// Listed out of order: parseCurve sorts the points by utilization
+	curve, err := parseCurve([]rearv1alpha1.UtilizationPoint{
+		{Utilization: 80, Multiplier: "2"},
+		{Utilization: 20, Multiplier: "0.8"},
+		{Utilization: 50, Multiplier: "1"},
+	})
+	if err != nil {
+		t.Fatalf("parseCurve: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		curve       []curvePoint
+		utilization float64
+		want        float64
+	}{
+		{name: "empty curve leaves prices unchanged", utilization: 90, want: 1},
+		{name: "below the first point", curve: curve, utilization: 5, want: 0.8},
+		{name: "at the first point", curve: curve, utilization: 20, want: 0.8},
+		{name: "between the first two points", curve: curve, utilization: 35, want: 0.9},
+		{name: "at an inner point", curve: curve, utilization: 50, want: 1},
+		{name: "between the last two points", curve: curve, utilization: 71, want: 1.7},
+		{name: "at the last point", curve: curve, utilization: 80, want: 2},
+		{name: "above the last point", curve: curve, utilization: 100, want: 2},
+		{name: "single point applies everywhere", curve: curve[:1], utilization: 60, want: 0.8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := curveMultiplier(tt.curve, tt.utilization); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("curveMultiplier(%v) = %f, want %f", tt.utilization, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCurveInvalid(t *testing.T) {
+	if _, err := parseCurve([]rearv1alpha1.UtilizationPoint{{Utilization: 50, Multiplier: "double"}}); err == nil {
+		t.Errorf("parseCurve succeeded, want an error")
+	}
+}
+
+func TestTimeOfDayMultiplier(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, time.March, 12, hour, minute, 0, 0, time.UTC)
+	}
+	peak := rearv1alpha1.TimeOfDayMultiplier{Start: "09:00", End: "18:00", Multiplier: "1.5"}
+	night := rearv1alpha1.TimeOfDayMultiplier{Start: "22:00", End: "06:00", Multiplier: "0.5"}
+
+	tests := []struct {
+		name     string
+		windows  []rearv1alpha1.TimeOfDayMultiplier
+		timeZone string
+		now      time.Time
+		want     float64
+	}{
+		{name: "no windows", now: at(12, 0), want: 1},
+		{name: "inside a window", windows: []rearv1alpha1.TimeOfDayMultiplier{peak}, now: at(12, 0), want: 1.5},
+		{name: "window start is inclusive", windows: []rearv1alpha1.TimeOfDayMultiplier{peak}, now: at(9, 0), want: 1.5},
+		{name: "window end is exclusive", windows: []rearv1alpha1.TimeOfDayMultiplier{peak}, now: at(18, 0), want: 1},
+		{name: "last minute of a window", windows: []rearv1alpha1.TimeOfDayMultiplier{peak}, now: at(17, 59), want: 1.5},
+		{name: "outside every window", windows: []rearv1alpha1.TimeOfDayMultiplier{peak, night}, now: at(20, 0), want: 1},
+		{name: "window crossing midnight, before midnight", windows: []rearv1alpha1.TimeOfDayMultiplier{night}, now: at(23, 30), want: 0.5},
+		{name: "window crossing midnight, after midnight", windows: []rearv1alpha1.TimeOfDayMultiplier{night}, now: at(3, 0), want: 0.5},
+		{name: "window crossing midnight, end is exclusive", windows: []rearv1alpha1.TimeOfDayMultiplier{night}, now: at(6, 0), want: 1},
+		{
+			name:    "window with the same start and end spans the whole day",
+			windows: []rearv1alpha1.TimeOfDayMultiplier{{Start: "00:00", End: "00:00", Multiplier: "1.2"}},
+			now:     at(15, 45),
+			want:    1.2,
+		},
+		{
+			name: "overlapping windows, the first listed wins",
+			windows: []rearv1alpha1.TimeOfDayMultiplier{
+				peak,
+				{Start: "12:00", End: "14:00", Multiplier: "3"},
+			},
+			now:  at(13, 0),
+			want: 1.5,
+		},
+		{
+			name:     "windows are in the configured time zone",
+			windows:  []rearv1alpha1.TimeOfDayMultiplier{peak},
+			timeZone: "Europe/Rome",
+			// 08:30 UTC is 09:30 in Rome (UTC+1)
+			now:  at(8, 30),
+			want: 1.5,
+		},
+		{
+			name:     "time zone moves the end of a window",
+			windows:  []rearv1alpha1.TimeOfDayMultiplier{peak},
+			timeZone: "Europe/Rome",
+			// 17:30 UTC is 18:30 in Rome
+			now:  at(17, 30),
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := timeOfDayMultiplier(tt.windows, tt.timeZone, tt.now)
+			if err != nil {
+				t.Fatalf("timeOfDayMultiplier: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("timeOfDayMultiplier at %s = %f, want %f", tt.now.Format("15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeOfDayMultiplierInvalid(t *testing.T) {
+	now := time.Date(2026, time.March, 12, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		windows  []rearv1alpha1.TimeOfDayMultiplier
+		timeZone string
+	}{
+		{name: "unknown time zone", timeZone: "Mars/Olympus"},
+		{name: "invalid start", windows: []rearv1alpha1.TimeOfDayMultiplier{{Start: "9am", End: "18:00", Multiplier: "1"}}},
+		{name: "invalid end", windows: []rearv1alpha1.TimeOfDayMultiplier{{Start: "09:00", End: "24:00", Multiplier: "1"}}},
+		{name: "invalid multiplier", windows: []rearv1alpha1.TimeOfDayMultiplier{{Start: "09:00", End: "18:00", Multiplier: "-1"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := timeOfDayMultiplier(tt.windows, tt.timeZone, now); err == nil {
+				t.Errorf("timeOfDayMultiplier succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestScalePrice(t *testing.T) {
+	dynamic := &rearv1alpha1.DynamicPricing{
+		Floor:   rearv1alpha1.ResourcePrices{CPU: "0.02", Memory: "0.004"},
+		Ceiling: rearv1alpha1.ResourcePrices{CPU: "0.08", GPU: "3"},
+	}
+
+	tests := []struct {
+		name       string
+		price      string
+		multiplier float64
+		resource   string
+		want       string
+		wantBound  string
+	}{
+		{name: "within the bounds", price: "0.04", multiplier: 1.5, resource: resourceCPU, want: "0.06"},
+		{name: "raised to the floor", price: "0.04", multiplier: 0.25, resource: resourceCPU, want: "0.02", wantBound: rearv1alpha1.PriceBoundFloor},
+		{name: "lowered to the ceiling", price: "0.04", multiplier: 3, resource: resourceCPU, want: "0.08", wantBound: rearv1alpha1.PriceBoundCeiling},
+		{name: "exactly at the floor is not bounded", price: "0.04", multiplier: 0.5, resource: resourceCPU, want: "0.02"},
+		{name: "exactly at the ceiling is not bounded", price: "0.04", multiplier: 2, resource: resourceCPU, want: "0.08"},
+		{name: "floor without a ceiling", price: "0.005", multiplier: 10, resource: resourceMemory, want: "0.05"},
+		{name: "ceiling without a floor", price: "2", multiplier: 0, resource: resourceGPU, want: "0"},
+		{name: "bounds of other resources do not apply", price: "0.0001", multiplier: 100, resource: resourceStorage, want: "0.01"},
+		{name: "rounding keeps six decimals", price: "0.001", multiplier: 1.0 / 3, resource: resourceStorage, want: "0.000333"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, bound, err := scalePrice(tt.price, tt.multiplier, dynamic, tt.resource)
+			if err != nil {
+				t.Fatalf("scalePrice: %v", err)
+			}
+			if got != tt.want || bound != tt.wantBound {
+				t.Errorf("scalePrice(%s, %f) = %s, %q, want %s, %q", tt.price, tt.multiplier, got, bound, tt.want, tt.wantBound)
+			}
+		})
+	}
+}
+
+func TestValidateBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		floor   rearv1alpha1.ResourcePrices
+		ceiling rearv1alpha1.ResourcePrices
+		wantErr bool
+	}{
+		{name: "no bounds"},
+		{name: "floor below ceiling", floor: rearv1alpha1.ResourcePrices{CPU: "0.01"}, ceiling: rearv1alpha1.ResourcePrices{CPU: "0.1"}},
+		{name: "floor equal to ceiling", floor: rearv1alpha1.ResourcePrices{CPU: "0.05"}, ceiling: rearv1alpha1.ResourcePrices{CPU: "0.05"}},
+		{name: "floor and ceiling of different resources", floor: rearv1alpha1.ResourcePrices{CPU: "1"}, ceiling: rearv1alpha1.ResourcePrices{Memory: "0.1"}},
+		{name: "floor above ceiling", floor: rearv1alpha1.ResourcePrices{GPU: "3"}, ceiling: rearv1alpha1.ResourcePrices{GPU: "2"}, wantErr: true},
+		{name: "invalid floor", floor: rearv1alpha1.ResourcePrices{Storage: "free"}, wantErr: true},
+		{name: "invalid ceiling", ceiling: rearv1alpha1.ResourcePrices{Memory: "-1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateBounds(tt.floor, tt.ceiling); (err != nil) != tt.wantErr {
+				t.Errorf("validateBounds = %v, want an error: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCostDynamic(t *testing.T) {
+	// 75% of the CPU and 10% of the memory is allocated, at 12:00 UTC
+	resources := &rearv1alpha1.ResourceMetrics{
+		Allocatable: rearv1alpha1.ResourceQuantities{CPU: resource.MustParse("8"), Memory: resource.MustParse("100Gi")},
+		Allocated:   rearv1alpha1.ResourceQuantities{CPU: resource.MustParse("6"), Memory: resource.MustParse("10Gi")},
+	}
+	now := time.Date(2026, time.March, 12, 12, 0, 0, 0, time.UTC)
+
+	spec := &rearv1alpha1.PricingPolicySpec{
+		Currency: "EUR",
+		Default:  rearv1alpha1.ResourcePrices{CPU: "0.04", Memory: "0.005"},
+		NodePools: []rearv1alpha1.NodePoolPrices{
+			{Name: "fast", Prices: rearv1alpha1.ResourcePrices{CPU: "0.1"}},
+		},
+		Dynamic: &rearv1alpha1.DynamicPricing{
+			UtilizationCurve: []rearv1alpha1.UtilizationPoint{
+				{Utilization: 50, Multiplier: "1"},
+				{Utilization: 100, Multiplier: "2"},
+			},
+			TimeOfDay: []rearv1alpha1.TimeOfDayMultiplier{{Start: "09:00", End: "18:00", Multiplier: "1.2"}},
+			Ceiling:   rearv1alpha1.ResourcePrices{CPU: "0.15"},
+		},
+	}
+	pools := []rearv1alpha1.NodePoolResources{
+		pool("default", "6", "50Gi", "", ""),
+		pool("fast", "2", "50Gi", "", ""),
+	}
+
+	cost, status, err := Cost(spec, resources, pools, now)
+	if err != nil {
+		t.Fatalf("Cost: %v", err)
+	}
+	if status == nil {
+		t.Fatalf("Cost returned no pricing status with dynamic pricing")
+	}
+	if status.TimeOfDayMultiplier != "1.2" {
+		t.Errorf("TimeOfDayMultiplier = %s, want 1.2", status.TimeOfDayMultiplier)
+	}
+
+	// cpu: base (6*0.04 + 2*0.1) / 8 = 0.055, multiplier 1.5 * 1.2 = 1.8
+	// memory: below the curve, multiplier 1 * 1.2
+	want := []rearv1alpha1.ResourcePricing{
+		{Resource: resourceCPU, BasePrice: "0.055", Utilization: 75, UtilizationMultiplier: "1.5", Price: "0.099"},
+		{Resource: resourceMemory, BasePrice: "0.005", Utilization: 10, UtilizationMultiplier: "1", Price: "0.006"},
+	}
+	if len(status.Resources) != len(want) {
+		t.Fatalf("status records %d resources, want %d: %+v", len(status.Resources), len(want), status.Resources)
+	}
+	for i := range want {
+		if status.Resources[i] != want[i] {
+			t.Errorf("status.Resources[%d] = %+v, want %+v", i, status.Resources[i], want[i])
+		}
+	}
+
+	if cost.CPUCost != "0.099" || cost.MemoryCost != "0.006" {
+		t.Errorf("CPUCost, MemoryCost = %s, %s, want 0.099, 0.006", cost.CPUCost, cost.MemoryCost)
+	}
+	// Pool prices get the same multiplier and are bounded separately: 0.1 * 1.8 exceeds the ceiling
+	wantPools := map[string]string{"default": "0.072", "fast": "0.15"}
+	for _, p := range cost.NodePools {
+		if p.CPUCost != wantPools[p.Name] {
+			t.Errorf("pool %s CPUCost = %s, want %s", p.Name, p.CPUCost, wantPools[p.Name])
+		}
+		if p.MemoryCost != "0.006" {
+			t.Errorf("pool %s MemoryCost = %s, want 0.006", p.Name, p.MemoryCost)
+		}
+	}
+}
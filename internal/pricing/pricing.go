@@ -0,0 +1,180 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// priceDecimals is the precision of the computed prices
+const priceDecimals = 6
+
+// weightedPrice accumulates a price averaged over node pools, weighted by their allocatable amount
+type weightedPrice struct {
+	sum    float64
+	weight float64
+}
+
+// add records the price of a pool holding the given amount of the resource
+func (w *weightedPrice) add(price string, amount float64) error {
+	if price == "" || amount <= 0 {
+		return nil
+	}
+	value, err := parsePrice(price)
+	if err != nil {
+		return err
+	}
+	w.sum += value * amount
+	w.weight += amount
+	return nil
+}
+
+// format returns the average price, or fallback when no pool contributed
+func (w *weightedPrice) format(fallback string) string {
+	if w.weight == 0 {
+		return fallback
+	}
+	return formatPrice(w.sum / w.weight)
+}
+
+// Cost computes the advertised cost information from a pricing policy.
+// When pools is empty, the default prices apply to the whole cluster; otherwise each
+// pool gets its own prices and the cluster-wide prices are their weighted average.
+// With dynamic pricing, the prices are then adjusted to the utilization of resources
+// and to the time of day at now, and the returned status records the computation.
+// A nil spec yields no cost information.
+func Cost(
+	spec *rearv1alpha1.PricingPolicySpec,
+	resources *rearv1alpha1.ResourceMetrics,
+	pools []rearv1alpha1.NodePoolResources,
+	now time.Time,
+) (*rearv1alpha1.CostInfo, *rearv1alpha1.PricingStatus, error) {
+	if spec == nil {
+		return nil, nil, nil
+	}
+
+	cost, err := staticCost(spec, pools)
+	if err != nil {
+		return nil, nil, err
+	}
+	if spec.Dynamic == nil {
+		return cost, nil, nil
+	}
+
+	status, err := adjust(cost, spec.Dynamic, resources, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid dynamic pricing: %w", err)
+	}
+	return cost, status, nil
+}
+
+// staticCost computes the configured prices of the cluster and of each node pool
+func staticCost(spec *rearv1alpha1.PricingPolicySpec, pools []rearv1alpha1.NodePoolResources) (*rearv1alpha1.CostInfo, error) {
+	if err := validatePrices(spec.Default); err != nil {
+		return nil, fmt.Errorf("invalid default prices: %w", err)
+	}
+
+	cost := &rearv1alpha1.CostInfo{
+		CPUCost:     spec.Default.CPU,
+		MemoryCost:  spec.Default.Memory,
+		GPUCost:     spec.Default.GPU,
+		StorageCost: spec.Default.Storage,
+		Currency:    spec.Currency,
+	}
+	if len(pools) == 0 {
+		return cost, nil
+	}
+
+	overrides := make(map[string]rearv1alpha1.ResourcePrices, len(spec.NodePools))
+	for _, pool := range spec.NodePools {
+		if err := validatePrices(pool.Prices); err != nil {
+			return nil, fmt.Errorf("invalid prices for node pool %s: %w", pool.Name, err)
+		}
+		overrides[pool.Name] = pool.Prices
+	}
+
+	var cpu, memory, gpu, storage weightedPrice
+	for _, pool := range pools {
+		prices := effectivePrices(spec.Default, overrides[pool.Name])
+		cost.NodePools = append(cost.NodePools, rearv1alpha1.NodePoolCost{
+			Name:        pool.Name,
+			CPUCost:     prices.CPU,
+			MemoryCost:  prices.Memory,
+			GPUCost:     prices.GPU,
+			StorageCost: prices.Storage,
+		})
+
+		if err := cpu.add(prices.CPU, pool.Allocatable.CPU.AsApproximateFloat64()); err != nil {
+			return nil, err
+		}
+		if err := memory.add(prices.Memory, pool.Allocatable.Memory.AsApproximateFloat64()); err != nil {
+			return nil, err
+		}
+		if pool.Allocatable.GPU != nil {
+			if err := gpu.add(prices.GPU, pool.Allocatable.GPU.AsApproximateFloat64()); err != nil {
+				return nil, err
+			}
+		}
+		if pool.Allocatable.Storage != nil {
+			if err := storage.add(prices.Storage, pool.Allocatable.Storage.AsApproximateFloat64()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	cost.CPUCost = cpu.format(spec.Default.CPU)
+	cost.MemoryCost = memory.format(spec.Default.Memory)
+	cost.GPUCost = gpu.format(spec.Default.GPU)
+	cost.StorageCost = storage.format(spec.Default.Storage)
+
+	return cost, nil
+}
+
+// effectivePrices fills the prices a pool does not override with the defaults
+func effectivePrices(defaults, override rearv1alpha1.ResourcePrices) rearv1alpha1.ResourcePrices {
+	prices := defaults
+	if override.CPU != "" {
+		prices.CPU = override.CPU
+	}
+	if override.Memory != "" {
+		prices.Memory = override.Memory
+	}
+	if override.GPU != "" {
+		prices.GPU = override.GPU
+	}
+	if override.Storage != "" {
+		prices.Storage = override.Storage
+	}
+	return prices
+}
+
+// validatePrices checks that every set price is a non-negative decimal
+func validatePrices(prices rearv1alpha1.ResourcePrices) error {
+	for _, price := range []string{prices.CPU, prices.Memory, prices.GPU, prices.Storage} {
+		if price == "" {
+			continue
+		}
+		if _, err := parsePrice(price); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parsePrice parses a non-negative decimal price
+func parsePrice(price string) (float64, error) {
+	value, err := strconv.ParseFloat(price, 64)
+	if err != nil || value < 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+		return 0, fmt.Errorf("invalid price %q", price)
+	}
+	return value, nil
+}
+
+// formatPrice renders a price with a bounded number of decimals
+func formatPrice(value float64) string {
+	scale := math.Pow10(priceDecimals)
+	return strconv.FormatFloat(math.Round(value*scale)/scale, 'f', -1, 64)
+}
@@ -0,0 +1,238 @@
+package pricing
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	rearv1alpha1 "github.com/mehdiazizian/liqo-resource-agent/api/v1alpha1"
+)
+
+// pool builds a node pool with the given allocatable amounts; gpu and storage are omitted when empty
+func pool(name, cpu, memory, gpu, storage string) rearv1alpha1.NodePoolResources {
+	allocatable := rearv1alpha1.ResourceQuantities{
+		CPU:    resource.MustParse(cpu),
+		Memory: resource.MustParse(memory),
+	}
+	if gpu != "" {
+		g := resource.MustParse(gpu)
+		allocatable.GPU = &g
+	}
+	if storage != "" {
+		s := resource.MustParse(storage)
+		allocatable.Storage = &s
+	}
+	return rearv1alpha1.NodePoolResources{Name: name, Allocatable: allocatable}
+}
+
+func TestCostWeightedAverage(t *testing.T) {
+	defaults := rearv1alpha1.ResourcePrices{CPU: "0.04", Memory: "0.005", GPU: "2", Storage: "0.0001"}
+
+	tests := []struct {
+		name      string
+		overrides []rearv1alpha1.NodePoolPrices
+		pools     []rearv1alpha1.NodePoolResources
+		want      *rearv1alpha1.CostInfo
+	}{
+		{
+			name: "without pools the defaults apply",
+			overrides: []rearv1alpha1.NodePoolPrices{
+				{Name: "fast", Prices: rearv1alpha1.ResourcePrices{CPU: "1"}},
+			},
+			want: &rearv1alpha1.CostInfo{CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.0001", Currency: "EUR"},
+		},
+		{
+			name:  "pool without overrides gets the defaults",
+			pools: []rearv1alpha1.NodePoolResources{pool("default", "8", "32Gi", "", "")},
+			want: &rearv1alpha1.CostInfo{
+				CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.0001", Currency: "EUR",
+				NodePools: []rearv1alpha1.NodePoolCost{
+					{Name: "default", CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.0001"},
+				},
+			},
+		},
+		{
+			name: "cluster prices are weighted by the allocatable amount of each pool",
+			overrides: []rearv1alpha1.NodePoolPrices{
+				{Name: "fast", Prices: rearv1alpha1.ResourcePrices{CPU: "0.09", Memory: "0.007"}},
+			},
+			pools: []rearv1alpha1.NodePoolResources{
+				pool("default", "8", "32Gi", "", ""),
+				pool("fast", "2", "32Gi", "", ""),
+			},
+			// cpu: (8*0.04 + 2*0.09) / 10, memory: (0.005 + 0.007) / 2
+			want: &rearv1alpha1.CostInfo{
+				CPUCost: "0.05", MemoryCost: "0.006", GPUCost: "2", StorageCost: "0.0001", Currency: "EUR",
+				NodePools: []rearv1alpha1.NodePoolCost{
+					{Name: "default", CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.0001"},
+					{Name: "fast", CPUCost: "0.09", MemoryCost: "0.007", GPUCost: "2", StorageCost: "0.0001"},
+				},
+			},
+		},
+		{
+			name: "pool without allocatable carries no weight",
+			overrides: []rearv1alpha1.NodePoolPrices{
+				{Name: "drained", Prices: rearv1alpha1.ResourcePrices{CPU: "1", Memory: "1"}},
+			},
+			pools: []rearv1alpha1.NodePoolResources{
+				pool("default", "8", "32Gi", "", ""),
+				pool("drained", "0", "0", "", ""),
+			},
+			want: &rearv1alpha1.CostInfo{
+				CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.0001", Currency: "EUR",
+				NodePools: []rearv1alpha1.NodePoolCost{
+					{Name: "default", CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.0001"},
+					{Name: "drained", CPUCost: "1", MemoryCost: "1", GPUCost: "2", StorageCost: "0.0001"},
+				},
+			},
+		},
+		{
+			name: "only pools with GPUs weigh on the GPU price",
+			overrides: []rearv1alpha1.NodePoolPrices{
+				{Name: "gpu", Prices: rearv1alpha1.ResourcePrices{GPU: "3"}},
+				{Name: "a100", Prices: rearv1alpha1.ResourcePrices{GPU: "6"}},
+			},
+			pools: []rearv1alpha1.NodePoolResources{
+				pool("default", "8", "32Gi", "", ""),
+				pool("gpu", "8", "32Gi", "2", ""),
+				pool("a100", "8", "32Gi", "1", ""),
+			},
+			// gpu: (2*3 + 1*6) / 3
+			want: &rearv1alpha1.CostInfo{
+				CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "4", StorageCost: "0.0001", Currency: "EUR",
+				NodePools: []rearv1alpha1.NodePoolCost{
+					{Name: "default", CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.0001"},
+					{Name: "gpu", CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "3", StorageCost: "0.0001"},
+					{Name: "a100", CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "6", StorageCost: "0.0001"},
+				},
+			},
+		},
+		{
+			name: "pool storage prices are kept and weighted",
+			overrides: []rearv1alpha1.NodePoolPrices{
+				{Name: "nvme", Prices: rearv1alpha1.ResourcePrices{Storage: "0.0002"}},
+			},
+			pools: []rearv1alpha1.NodePoolResources{
+				pool("default", "8", "32Gi", "", "100Gi"),
+				pool("nvme", "8", "32Gi", "", "300Gi"),
+			},
+			// storage: (100*0.0001 + 300*0.0002) / 400
+			want: &rearv1alpha1.CostInfo{
+				CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.000175", Currency: "EUR",
+				NodePools: []rearv1alpha1.NodePoolCost{
+					{Name: "default", CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.0001"},
+					{Name: "nvme", CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.0002"},
+				},
+			},
+		},
+		{
+			name: "overrides of absent pools are ignored",
+			overrides: []rearv1alpha1.NodePoolPrices{
+				{Name: "gone", Prices: rearv1alpha1.ResourcePrices{CPU: "1"}},
+			},
+			pools: []rearv1alpha1.NodePoolResources{pool("default", "8", "32Gi", "", "")},
+			want: &rearv1alpha1.CostInfo{
+				CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.0001", Currency: "EUR",
+				NodePools: []rearv1alpha1.NodePoolCost{
+					{Name: "default", CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.0001"},
+				},
+			},
+		},
+		{
+			name: "averages are rounded to the price precision",
+			overrides: []rearv1alpha1.NodePoolPrices{
+				{Name: "fast", Prices: rearv1alpha1.ResourcePrices{CPU: "0.1"}},
+			},
+			pools: []rearv1alpha1.NodePoolResources{
+				pool("default", "2", "32Gi", "", ""),
+				pool("fast", "1", "32Gi", "", ""),
+			},
+			// cpu: (2*0.04 + 1*0.1) / 3
+			want: &rearv1alpha1.CostInfo{
+				CPUCost: "0.06", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.0001", Currency: "EUR",
+				NodePools: []rearv1alpha1.NodePoolCost{
+					{Name: "default", CPUCost: "0.04", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.0001"},
+					{Name: "fast", CPUCost: "0.1", MemoryCost: "0.005", GPUCost: "2", StorageCost: "0.0001"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &rearv1alpha1.PricingPolicySpec{Currency: "EUR", Default: defaults, NodePools: tt.overrides}
+			got, status, err := Cost(spec, nil, tt.pools, time.Now())
+			if err != nil {
+				t.Fatalf("Cost: %v", err)
+			}
+			if status != nil {
+				t.Errorf("Cost returned a pricing status without dynamic pricing: %+v", status)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cost = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCostWeightedAverageFallsBackToDefaults(t *testing.T) {
+	// No pool has GPUs or storage, so the cluster-wide prices are the defaults
+	spec := &rearv1alpha1.PricingPolicySpec{
+		Default: rearv1alpha1.ResourcePrices{CPU: "0.04", Memory: "0.005", GPU: "2", Storage: "0.0001"},
+		NodePools: []rearv1alpha1.NodePoolPrices{
+			{Name: "fast", Prices: rearv1alpha1.ResourcePrices{GPU: "5", Storage: "0.0003"}},
+		},
+	}
+	got, _, err := Cost(spec, nil, []rearv1alpha1.NodePoolResources{pool("fast", "4", "16Gi", "", "")}, time.Now())
+	if err != nil {
+		t.Fatalf("Cost: %v", err)
+	}
+	if got.GPUCost != "2" || got.StorageCost != "0.0001" {
+		t.Errorf("GPUCost, StorageCost = %s, %s, want the defaults 2, 0.0001", got.GPUCost, got.StorageCost)
+	}
+	if pool := got.NodePools[0]; pool.GPUCost != "5" || pool.StorageCost != "0.0003" {
+		t.Errorf("pool GPUCost, StorageCost = %s, %s, want the overrides 5, 0.0003", pool.GPUCost, pool.StorageCost)
+	}
+}
+
+func TestCostInvalidPrices(t *testing.T) {
+	pools := []rearv1alpha1.NodePoolResources{pool("default", "8", "32Gi", "", "")}
+
+	tests := []struct {
+		name string
+		spec *rearv1alpha1.PricingPolicySpec
+	}{
+		{
+			name: "invalid default price",
+			spec: &rearv1alpha1.PricingPolicySpec{Default: rearv1alpha1.ResourcePrices{CPU: "cheap"}},
+		},
+		{
+			name: "negative default price",
+			spec: &rearv1alpha1.PricingPolicySpec{Default: rearv1alpha1.ResourcePrices{Memory: "-0.1"}},
+		},
+		{
+			name: "invalid pool price",
+			spec: &rearv1alpha1.PricingPolicySpec{
+				Default:   rearv1alpha1.ResourcePrices{CPU: "0.04"},
+				NodePools: []rearv1alpha1.NodePoolPrices{{Name: "default", Prices: rearv1alpha1.ResourcePrices{Storage: "NaN"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Cost(tt.spec, nil, pools, time.Now()); err == nil {
+				t.Errorf("Cost succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestCostWithoutPolicy(t *testing.T) {
+	cost, status, err := Cost(nil, nil, nil, time.Now())
+	if cost != nil || status != nil || err != nil {
+		t.Errorf("Cost(nil) = %v, %v, %v, want nil, nil, nil", cost, status, err)
+	}
+}
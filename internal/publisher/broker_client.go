@@ -6,9 +6,13 @@ import (
 	"os"
 	"path/filepath"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -30,6 +34,16 @@ import (
 // approach for new deployments.
 // ============================================================================
 
+// gpuResourceName is the resource advertised in the dedicated GPU field
+const gpuResourceName = "nvidia.com/gpu"
+
+// clusterAdvertisementGVR identifies the broker's ClusterAdvertisement resource
+var clusterAdvertisementGVR = schema.GroupVersionResource{
+	Group:    "broker.fluidos.eu",
+	Version:  "v1alpha1",
+	Resource: "clusteradvertisements",
+}
+
 // BrokerClient publishes advertisements to the broker cluster (LEGACY)
 type BrokerClient struct {
 	Client    dynamic.Interface
@@ -92,18 +106,8 @@ func (b *BrokerClient) PublishAdvertisement(ctx context.Context, adv *rearv1alph
 		return nil
 	}
 
-	// Define the GVR for ClusterAdvertisement
-	gvr := schema.GroupVersionResource{
-		Group:    "broker.fluidos.eu",
-		Version:  "v1alpha1",
-		Resource: "clusteradvertisements",
-	}
-
-	namespace := b.Namespace
-	if namespace == "" {
-		namespace = "default"
-	}
-	resourceClient := b.Client.Resource(gvr).Namespace(namespace)
+	namespace := b.brokerNamespace()
+	resourceClient := b.Client.Resource(clusterAdvertisementGVR).Namespace(namespace)
 
 	// Try to get existing advertisement to preserve resourceVersion for optimistic concurrency
 	existing, err := resourceClient.Get(ctx, fmt.Sprintf("%s-adv", b.ClusterID), metav1.GetOptions{})
@@ -113,24 +117,13 @@ func (b *BrokerClient) PublishAdvertisement(ctx context.Context, adv *rearv1alph
 	// The Available value already accounts for locally reserved resources (via ProviderInstructions).
 	// The broker manages its own Reserved field independently for immediate resource locking.
 	// The broker's ClusterAdvertisementReconciler will recalculate Available using the fresh
-	// Allocatable/Allocated we provide here, combined with its own Reserved tracking.
+	// Allocatable/Allocated we provide here, combined with its own Reserved tracking, so
+	// Allocatable is adjusted for whatever lowered (or raised) Available: see lendableAllocatable.
 	resourcesSpec := map[string]interface{}{
-		"capacity": map[string]interface{}{
-			"cpu":    adv.Spec.Resources.Capacity.CPU.String(),
-			"memory": adv.Spec.Resources.Capacity.Memory.String(),
-		},
-		"allocatable": map[string]interface{}{
-			"cpu":    adv.Spec.Resources.Allocatable.CPU.String(),
-			"memory": adv.Spec.Resources.Allocatable.Memory.String(),
-		},
-		"allocated": map[string]interface{}{
-			"cpu":    adv.Spec.Resources.Allocated.CPU.String(),
-			"memory": adv.Spec.Resources.Allocated.Memory.String(),
-		},
-		"available": map[string]interface{}{
-			"cpu":    adv.Spec.Resources.Available.CPU.String(),
-			"memory": adv.Spec.Resources.Available.Memory.String(),
-		},
+		"capacity":    quantitiesPayload(adv.Spec.Resources.Capacity),
+		"allocatable": quantitiesPayload(lendableAllocatable(adv)),
+		"allocated":   quantitiesPayload(adv.Spec.Resources.Allocated),
+		"available":   quantitiesPayload(adv.Spec.Resources.Available),
 	}
 
 	// Preserve the broker's Reserved field if it exists
@@ -145,6 +138,57 @@ func (b *BrokerClient) PublishAdvertisement(ctx context.Context, adv *rearv1alph
 		}
 	}
 
+	spec := map[string]interface{}{
+		"clusterID":   adv.Spec.ClusterID,
+		"clusterName": b.ClusterID,
+		"resources":   resourcesSpec,
+		"timestamp":   adv.Spec.Timestamp.Format("2006-01-02T15:04:05Z"),
+	}
+	if adv.Spec.Cost != nil {
+		spec["cost"] = costPayload(adv.Spec.Cost)
+	}
+	if len(adv.Spec.Forecast) > 0 {
+		forecasts := make([]interface{}, 0, len(adv.Spec.Forecast))
+		for _, forecast := range adv.Spec.Forecast {
+			forecasts = append(forecasts, map[string]interface{}{
+				"horizon":   forecast.Horizon.Duration.String(),
+				"available": quantitiesPayload(forecast.Available),
+				"method":    forecast.Method,
+				"samples":   int64(forecast.Samples),
+			})
+		}
+		spec["forecast"] = forecasts
+	}
+	if maintenance := adv.Spec.Maintenance; maintenance != nil && maintenance.Enabled {
+		spec["maintenance"] = map[string]interface{}{"reason": maintenance.Reason}
+	}
+	if schedule := adv.Status.Schedule; schedule != nil {
+		schedulePayload := map[string]interface{}{"lending": schedule.Lending}
+		if schedule.Window != "" {
+			schedulePayload["window"] = schedule.Window
+		}
+		if schedule.NextTransition != nil {
+			schedulePayload["nextTransition"] = schedule.NextTransition.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		spec["schedule"] = schedulePayload
+	}
+	if energy := adv.Spec.Energy; energy != nil {
+		energyPayload := map[string]interface{}{
+			"source":     energy.Source,
+			"observedAt": energy.ObservedAt.Format("2006-01-02T15:04:05Z"),
+		}
+		if energy.CarbonIntensity != "" {
+			energyPayload["carbonIntensity"] = energy.CarbonIntensity
+		}
+		if energy.EnergyCost != "" {
+			energyPayload["energyCost"] = energy.EnergyCost
+		}
+		if energy.Currency != "" {
+			energyPayload["currency"] = energy.Currency
+		}
+		spec["energy"] = energyPayload
+	}
+
 	// Convert to unstructured
 	clusterAdv := &unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -154,12 +198,7 @@ func (b *BrokerClient) PublishAdvertisement(ctx context.Context, adv *rearv1alph
 				"name":      fmt.Sprintf("%s-adv", b.ClusterID),
 				"namespace": namespace,
 			},
-			"spec": map[string]interface{}{
-				"clusterID":   adv.Spec.ClusterID,
-				"clusterName": b.ClusterID,
-				"resources":   resourcesSpec,
-				"timestamp":   adv.Spec.Timestamp.Format("2006-01-02T15:04:05Z"),
-			},
+			"spec": spec,
 		},
 	}
 
@@ -184,3 +223,193 @@ func (b *BrokerClient) PublishAdvertisement(ctx context.Context, adv *rearv1alph
 
 	return nil
 }
+
+// WithdrawAdvertisement stops the broker from matching requests to this cluster without
+// deleting its ClusterAdvertisement: deleting it would also drop the Reserved field the
+// broker manages, and the next publish would recreate it without the reservations.
+// Allocatable is set to Allocated and Available to zero, so the broker's recalculation
+// leaves nothing to reserve. A missing ClusterAdvertisement is not an error.
+func (b *BrokerClient) WithdrawAdvertisement(ctx context.Context) error {
+	if !b.Enabled {
+		return nil
+	}
+
+	resourceClient := b.Client.Resource(clusterAdvertisementGVR).Namespace(b.brokerNamespace())
+	existing, err := resourceClient.Get(ctx, fmt.Sprintf("%s-adv", b.ClusterID), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get advertisement from broker: %w", err)
+	}
+
+	resources, found, err := unstructured.NestedMap(existing.Object, "spec", "resources")
+	if err != nil || !found {
+		return err
+	}
+	if allocated, ok := resources["allocated"].(map[string]interface{}); ok {
+		resources["allocatable"] = allocated
+	}
+	if available, ok := resources["available"].(map[string]interface{}); ok {
+		resources["available"] = zeroPayload(available)
+	}
+	if err := unstructured.SetNestedMap(existing.Object, resources, "spec", "resources"); err != nil {
+		return err
+	}
+
+	if _, err := resourceClient.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to withdraw advertisement from broker: %w", err)
+	}
+	return nil
+}
+
+// zeroPayload returns a copy of a quantities payload with every quantity set to zero
+func zeroPayload(payload map[string]interface{}) map[string]interface{} {
+	zeroed := make(map[string]interface{}, len(payload))
+	for name, value := range payload {
+		if nested, ok := value.(map[string]interface{}); ok {
+			zeroed[name] = zeroPayload(nested)
+			continue
+		}
+		zeroed[name] = "0"
+	}
+	return zeroed
+}
+
+// lendableAllocatable returns the Allocatable to publish so that the broker's recalculation
+// (Allocatable - Allocated) matches the advertised Available. Resources whose availability
+// was set by the AdvertisementPolicy, the lending schedule or the offload quota are published
+// as Allocated + Available; while nothing is lent, Allocatable is published as Allocated.
+// Resources left at their free capacity keep the node Allocatable.
+func lendableAllocatable(adv *rearv1alpha1.Advertisement) rearv1alpha1.ResourceQuantities {
+	resources := adv.Spec.Resources
+	if !lending(adv) {
+		return resources.Allocated
+	}
+
+	adjusted := sets.New[string]()
+	for _, constraint := range adv.Status.AvailabilityConstraints {
+		if constraint.Constraint != rearv1alpha1.ConstraintFreeCapacity {
+			adjusted.Insert(constraint.Resource)
+		}
+	}
+	allocatable := *resources.Allocatable.DeepCopy()
+	if adjusted.Len() == 0 {
+		return allocatable
+	}
+
+	adjust := func(dst, allocated, available *resource.Quantity) {
+		if dst == nil || allocated == nil || available == nil {
+			return
+		}
+		lendable := allocated.DeepCopy()
+		lendable.Add(*available)
+		*dst = lendable
+	}
+	if adjusted.Has(string(corev1.ResourceCPU)) {
+		adjust(&allocatable.CPU, &resources.Allocated.CPU, &resources.Available.CPU)
+	}
+	if adjusted.Has(string(corev1.ResourceMemory)) {
+		adjust(&allocatable.Memory, &resources.Allocated.Memory, &resources.Available.Memory)
+	}
+	if adjusted.Has(gpuResourceName) {
+		adjust(allocatable.GPU, resources.Allocated.GPU, resources.Available.GPU)
+	}
+	if adjusted.Has(string(corev1.ResourcePods)) {
+		adjust(allocatable.Pods, resources.Allocated.Pods, resources.Available.Pods)
+	}
+	if adjusted.Has(string(corev1.ResourceEphemeralStorage)) {
+		adjust(allocatable.EphemeralStorage, resources.Allocated.EphemeralStorage, resources.Available.EphemeralStorage)
+	}
+	// Storage may also cover node ephemeral storage
+	if adjusted.HasAny(string(corev1.ResourceStorage), string(corev1.ResourceEphemeralStorage)) {
+		adjust(allocatable.Storage, resources.Allocated.Storage, resources.Available.Storage)
+	}
+	for name, quantity := range allocatable.Extended {
+		allocated, okAllocated := resources.Allocated.Extended[name]
+		available, okAvailable := resources.Available.Extended[name]
+		if adjusted.Has(name) && okAllocated && okAvailable {
+			adjust(&quantity, &allocated, &available)
+			allocatable.Extended[name] = quantity
+		}
+	}
+	return allocatable
+}
+
+// lending reports whether the advertisement offers anything, i.e. the cluster is neither
+// in maintenance nor outside every lending window
+func lending(adv *rearv1alpha1.Advertisement) bool {
+	if maintenance := adv.Spec.Maintenance; maintenance != nil && maintenance.Enabled {
+		return false
+	}
+	return adv.Status.Schedule == nil || adv.Status.Schedule.Lending
+}
+
+// brokerNamespace returns the broker namespace holding ClusterAdvertisements
+func (b *BrokerClient) brokerNamespace() string {
+	if b.Namespace == "" {
+		return "default"
+	}
+	return b.Namespace
+}
+
+// costPayload converts CostInfo to the unstructured ClusterAdvertisement format, omitting unset prices
+func costPayload(cost *rearv1alpha1.CostInfo) map[string]interface{} {
+	payload := map[string]interface{}{}
+	setString := func(m map[string]interface{}, key, value string) {
+		if value != "" {
+			m[key] = value
+		}
+	}
+
+	setString(payload, "cpuCost", cost.CPUCost)
+	setString(payload, "memoryCost", cost.MemoryCost)
+	setString(payload, "gpuCost", cost.GPUCost)
+	setString(payload, "storageCost", cost.StorageCost)
+	setString(payload, "currency", cost.Currency)
+
+	if len(cost.NodePools) > 0 {
+		pools := make([]interface{}, 0, len(cost.NodePools))
+		for _, pool := range cost.NodePools {
+			entry := map[string]interface{}{"name": pool.Name}
+			setString(entry, "cpuCost", pool.CPUCost)
+			setString(entry, "memoryCost", pool.MemoryCost)
+			setString(entry, "gpuCost", pool.GPUCost)
+			setString(entry, "storageCost", pool.StorageCost)
+			pools = append(pools, entry)
+		}
+		payload["nodePools"] = pools
+	}
+
+	return payload
+}
+
+// quantitiesPayload converts ResourceQuantities to the unstructured ClusterAdvertisement format
+func quantitiesPayload(rq rearv1alpha1.ResourceQuantities) map[string]interface{} {
+	payload := map[string]interface{}{
+		"cpu":    rq.CPU.String(),
+		"memory": rq.Memory.String(),
+	}
+
+	if rq.Storage != nil {
+		payload["storage"] = rq.Storage.String()
+	}
+
+	if rq.EphemeralStorage != nil {
+		payload["ephemeralStorage"] = rq.EphemeralStorage.String()
+	}
+
+	if rq.Pods != nil {
+		payload["pods"] = rq.Pods.String()
+	}
+
+	if len(rq.Extended) > 0 {
+		extended := make(map[string]interface{}, len(rq.Extended))
+		for name, qty := range rq.Extended {
+			extended[name] = qty.String()
+		}
+		payload["extended"] = extended
+	}
+
+	return payload
+}
@@ -157,6 +157,7 @@ func (w *ReservationWatcher) Start(ctx context.Context) error {
 
 				cpu, _, _ := unstructured.NestedString(resources, "cpu")
 				memory, _, _ := unstructured.NestedString(resources, "memory")
+				storage, _, _ := unstructured.NestedString(resources, "storage")
 				expiresAt, _, _ := unstructured.NestedString(status, "expiresAt")
 
 				if requesterID == w.ClusterID {
@@ -187,11 +188,12 @@ func (w *ReservationWatcher) Start(ctx context.Context) error {
 						"requester", requesterID,
 						"requestedCPU", cpu,
 						"requestedMemory", memory,
+						"requestedStorage", storage,
 						"expiresAt", expiresAt,
 						"action", "reserve-resources")
 					if w.LocalClient != nil {
 						instructionName := fmt.Sprintf("%s-provider", unstructuredObj.GetName())
-						if err := w.upsertProviderInstruction(ctx, unstructuredObj, requesterID, cpu, memory, expiresAt); err != nil {
+						if err := w.upsertProviderInstruction(ctx, unstructuredObj, requesterID, cpu, memory, storage, expiresAt); err != nil {
 							logger.Error(err, "failed to persist provider instruction",
 								"instruction", instructionName,
 								"namespace", w.InstructionNamespace)
@@ -272,7 +274,7 @@ func (w *ReservationWatcher) upsertRequesterInstruction(
 func (w *ReservationWatcher) upsertProviderInstruction(
 	ctx context.Context,
 	reservation *unstructured.Unstructured,
-	requester, cpu, memory, expiresAt string,
+	requester, cpu, memory, storage, expiresAt string,
 ) error {
 	name := fmt.Sprintf("%s-provider", reservation.GetName())
 	ns := w.InstructionNamespace
@@ -285,6 +287,7 @@ func (w *ReservationWatcher) upsertProviderInstruction(
 		RequesterClusterID: requester,
 		RequestedCPU:       cpu,
 		RequestedMemory:    memory,
+		RequestedStorage:   storage,
 		Message: fmt.Sprintf("Hold %s CPU / %s Memory for requester %s",
 			cpu, memory, requester),
 	}
@@ -5,10 +5,134 @@ import "time"
 // AdvertisementDTO is a protocol-agnostic representation of cluster advertisement
 // It decouples business logic from transport protocol (HTTP, Kubernetes CRDs, etc.)
 type AdvertisementDTO struct {
-	ClusterID   string             `json:"clusterID"`
-	ClusterName string             `json:"clusterName"`
-	Resources   ResourceMetricsDTO `json:"resources"`
-	Timestamp   time.Time          `json:"timestamp"`
+	ClusterID      string             `json:"clusterID"`
+	ClusterName    string             `json:"clusterName"`
+	Resources      ResourceMetricsDTO `json:"resources"`
+	NodePools      []NodePoolDTO      `json:"nodePools,omitempty"`      // Per-pool breakdown for placement-feasible decisions
+	Fragmentation  *FragmentationDTO  `json:"fragmentation,omitempty"`  // Largest single-node shapes and free chunk histograms
+	StorageClasses []StorageClassDTO  `json:"storageClasses,omitempty"` // Persistent storage per StorageClass
+	Capabilities   *CapabilitiesDTO   `json:"capabilities,omitempty"`   // Topology, platforms and software versions
+	GPUs           []GPUModelDTO      `json:"gpus,omitempty"`           // GPUs per product and MIG profile
+	Cost           *CostDTO           `json:"cost,omitempty"`           // Hourly prices, when a PricingPolicy is configured
+	Energy         *EnergyDTO         `json:"energy,omitempty"`         // Carbon intensity and energy cost of the latest reading
+	Forecast       []ForecastDTO      `json:"forecast,omitempty"`       // Lowest predicted availability over the next hours
+	Schedule       *ScheduleDTO       `json:"schedule,omitempty"`       // Lending window in effect, when lending follows a schedule
+	Maintenance    *MaintenanceDTO    `json:"maintenance,omitempty"`    // Set while the cluster is withdrawn for maintenance
+	Timestamp      time.Time          `json:"timestamp"`
+}
+
+// NodePoolDTO represents the resources of a group of nodes sharing a pool label value
+type NodePoolDTO struct {
+	Name        string                `json:"name"`
+	NodeCount   int32                 `json:"nodeCount"`
+	Capacity    ResourceQuantitiesDTO `json:"capacity"`
+	Allocatable ResourceQuantitiesDTO `json:"allocatable"`
+	Allocated   ResourceQuantitiesDTO `json:"allocated"`
+	Available   ResourceQuantitiesDTO `json:"available"`
+}
+
+// CostDTO represents the hourly prices of the advertised resources
+type CostDTO struct {
+	CPUCost     string            `json:"cpuCost,omitempty"`     // Per core, e.g., "0.031"
+	MemoryCost  string            `json:"memoryCost,omitempty"`  // Per GB
+	GPUCost     string            `json:"gpuCost,omitempty"`     // Per GPU
+	StorageCost string            `json:"storageCost,omitempty"` // Per GB
+	Currency    string            `json:"currency,omitempty"`    // e.g., "EUR"
+	NodePools   []NodePoolCostDTO `json:"nodePools,omitempty"`
+}
+
+// MaintenanceDTO reports that the cluster accepts no new reservations
+type MaintenanceDTO struct {
+	Reason string `json:"reason,omitempty"` // e.g., "Kubernetes upgrade"
+}
+
+// ScheduleDTO represents the state of the lending schedule
+type ScheduleDTO struct {
+	Lending        bool       `json:"lending"`                  // False when nothing is lent until NextTransition
+	Window         string     `json:"window,omitempty"`         // Open window, empty outside every window
+	NextTransition *time.Time `json:"nextTransition,omitempty"` // When the window in effect changes
+}
+
+// ForecastDTO represents the lowest availability predicted within a window
+type ForecastDTO struct {
+	Horizon   string                `json:"horizon"` // e.g., "6h0m0s"
+	Available ResourceQuantitiesDTO `json:"available"`
+	Method    string                `json:"method"` // EWMA or Seasonal
+	Samples   int32                 `json:"samples"`
+}
+
+// EnergyDTO represents a carbon intensity and energy cost reading
+type EnergyDTO struct {
+	CarbonIntensity string    `json:"carbonIntensity,omitempty"` // gCO2eq per kWh, e.g., "212.5"
+	EnergyCost      string    `json:"energyCost,omitempty"`      // Per kWh
+	Currency        string    `json:"currency,omitempty"`
+	Source          string    `json:"source"` // static, file or http
+	ObservedAt      time.Time `json:"observedAt"`
+}
+
+// NodePoolCostDTO represents the hourly prices of a node pool
+type NodePoolCostDTO struct {
+	Name        string `json:"name"`
+	CPUCost     string `json:"cpuCost,omitempty"`
+	MemoryCost  string `json:"memoryCost,omitempty"`
+	GPUCost     string `json:"gpuCost,omitempty"`
+	StorageCost string `json:"storageCost,omitempty"`
+}
+
+// CapabilitiesDTO describes what kind of workloads the cluster can host
+type CapabilitiesDTO struct {
+	Regions           []string `json:"regions,omitempty"`
+	Zones             []string `json:"zones,omitempty"`
+	Architectures     []string `json:"architectures,omitempty"`    // e.g., ["amd64", "arm64"]
+	OperatingSystems  []string `json:"operatingSystems,omitempty"` // e.g., ["linux"]
+	KubernetesVersion string   `json:"kubernetesVersion,omitempty"`
+	CNIPlugin         string   `json:"cniPlugin,omitempty"`
+	CNIVersion        string   `json:"cniVersion,omitempty"`
+	LiqoVersion       string   `json:"liqoVersion,omitempty"`
+	GPUProducts       []string `json:"gpuProducts,omitempty"` // e.g., ["NVIDIA-A100-SXM4-40GB"]
+}
+
+// GPUModelDTO represents the GPUs of a single product
+type GPUModelDTO struct {
+	Product     string          `json:"product"`          // e.g., "NVIDIA-A100-SXM4-40GB"
+	Memory      string          `json:"memory,omitempty"` // Per GPU, e.g., "40Gi"
+	MIGStrategy string          `json:"migStrategy,omitempty"`
+	NodeCount   int32           `json:"nodeCount"`
+	Allocatable string          `json:"allocatable"`
+	Allocated   string          `json:"allocated"`
+	Available   string          `json:"available"`
+	MIGProfiles []MIGProfileDTO `json:"migProfiles,omitempty"`
+}
+
+// MIGProfileDTO represents the MIG devices of a single profile
+type MIGProfileDTO struct {
+	Profile     string `json:"profile"` // e.g., "1g.5gb"
+	Allocatable string `json:"allocatable"`
+	Allocated   string `json:"allocated"`
+	Available   string `json:"available"`
+}
+
+// StorageClassDTO represents the persistent storage offered through a StorageClass
+type StorageClassDTO struct {
+	Name        string `json:"name"`
+	Provisioner string `json:"provisioner"`
+	Capacity    string `json:"capacity,omitempty"` // Empty when the driver does not publish CSIStorageCapacity
+	Allocated   string `json:"allocated"`
+	Available   string `json:"available,omitempty"` // Empty when the driver does not publish CSIStorageCapacity
+}
+
+// FragmentationDTO describes how free resources are spread across nodes
+type FragmentationDTO struct {
+	LargestSchedulable ResourceQuantitiesDTO `json:"largestSchedulable"`
+	CPUChunks          []ChunkBucketDTO      `json:"cpuChunks,omitempty"`
+	MemoryChunks       []ChunkBucketDTO      `json:"memoryChunks,omitempty"`
+}
+
+// ChunkBucketDTO counts the nodes whose free amount falls in [LowerBound, UpperBound)
+type ChunkBucketDTO struct {
+	LowerBound string `json:"lowerBound"`
+	UpperBound string `json:"upperBound,omitempty"` // Empty for the last, unbounded bucket
+	Nodes      int32  `json:"nodes"`
 }
 
 // ResourceMetricsDTO represents resource metrics in a protocol-agnostic way
@@ -16,15 +140,32 @@ type ResourceMetricsDTO struct {
 	Capacity    ResourceQuantitiesDTO  `json:"capacity"`
 	Allocatable ResourceQuantitiesDTO  `json:"allocatable"`
 	Allocated   ResourceQuantitiesDTO  `json:"allocated"`
-	Reserved    *ResourceQuantitiesDTO `json:"reserved,omitempty"` // CRITICAL: Broker-managed field
+	Used        *ResourceQuantitiesDTO `json:"used,omitempty"`        // Actual consumption, when metrics.k8s.io is available
+	Borrowed    *ResourceQuantitiesDTO `json:"borrowed,omitempty"`    // Capacity of Liqo virtual nodes, not part of the figures above
+	Preemptible *ResourceQuantitiesDTO `json:"preemptible,omitempty"` // Allocated share held by low-priority pods
+	Pending     *ResourceQuantitiesDTO `json:"pending,omitempty"`     // Requests of pods waiting to be scheduled
+	Devices     []DeviceClassDTO       `json:"devices,omitempty"`     // DRA devices per DeviceClass
+	Reserved    *ResourceQuantitiesDTO `json:"reserved,omitempty"`    // CRITICAL: Broker-managed field
 	Available   ResourceQuantitiesDTO  `json:"available"`
 }
 
+// DeviceClassDTO counts the Dynamic Resource Allocation devices of a DeviceClass
+type DeviceClassDTO struct {
+	DeviceClass string `json:"deviceClass"`
+	Driver      string `json:"driver,omitempty"` // e.g., "gpu.nvidia.com"
+	Capacity    int32  `json:"capacity"`
+	Allocated   int32  `json:"allocated"`
+	Available   int32  `json:"available"`
+}
+
 // ResourceQuantitiesDTO represents resource quantities using strings
 // This avoids coupling to k8s.io/apimachinery/pkg/api/resource.Quantity
 type ResourceQuantitiesDTO struct {
-	CPU     string `json:"cpu"`               // e.g., "4000m" or "4"
-	Memory  string `json:"memory"`            // e.g., "8Gi" or "8589934592"
-	GPU     string `json:"gpu,omitempty"`     // e.g., "2"
-	Storage string `json:"storage,omitempty"` // e.g., "100Gi"
+	CPU              string            `json:"cpu"`                        // e.g., "4000m" or "4"
+	Memory           string            `json:"memory"`                     // e.g., "8Gi" or "8589934592"
+	GPU              string            `json:"gpu,omitempty"`              // e.g., "2"
+	Storage          string            `json:"storage,omitempty"`          // e.g., "100Gi"
+	EphemeralStorage string            `json:"ephemeralStorage,omitempty"` // e.g., "200Gi"
+	Pods             string            `json:"pods,omitempty"`             // e.g., "110"
+	Extended         map[string]string `json:"extended,omitempty"`         // e.g., {"amd.com/gpu": "4", "hugepages-2Mi": "1Gi"}
 }
@@ -23,6 +23,160 @@ func ToAdvertisementDTO(adv *rearv1alpha1.Advertisement) *AdvertisementDTO {
 		},
 	}
 
+	if adv.Spec.Resources.Used != nil {
+		used := toResourceQuantitiesDTO(*adv.Spec.Resources.Used)
+		dto.Resources.Used = &used
+	}
+
+	if adv.Spec.Resources.Borrowed != nil {
+		borrowed := toResourceQuantitiesDTO(*adv.Spec.Resources.Borrowed)
+		dto.Resources.Borrowed = &borrowed
+	}
+
+	if adv.Spec.Resources.Preemptible != nil {
+		preemptible := toResourceQuantitiesDTO(*adv.Spec.Resources.Preemptible)
+		dto.Resources.Preemptible = &preemptible
+	}
+
+	if adv.Spec.Resources.Pending != nil {
+		pending := toResourceQuantitiesDTO(*adv.Spec.Resources.Pending)
+		dto.Resources.Pending = &pending
+	}
+
+	for _, device := range adv.Spec.Resources.Devices {
+		dto.Resources.Devices = append(dto.Resources.Devices, DeviceClassDTO{
+			DeviceClass: device.DeviceClass,
+			Driver:      device.Driver,
+			Capacity:    device.Capacity,
+			Allocated:   device.Allocated,
+			Available:   device.Available,
+		})
+	}
+
+	for _, pool := range adv.Spec.NodePools {
+		dto.NodePools = append(dto.NodePools, NodePoolDTO{
+			Name:        pool.Name,
+			NodeCount:   pool.NodeCount,
+			Capacity:    toResourceQuantitiesDTO(pool.Capacity),
+			Allocatable: toResourceQuantitiesDTO(pool.Allocatable),
+			Allocated:   toResourceQuantitiesDTO(pool.Allocated),
+			Available:   toResourceQuantitiesDTO(pool.Available),
+		})
+	}
+
+	if frag := adv.Spec.Fragmentation; frag != nil {
+		dto.Fragmentation = &FragmentationDTO{
+			LargestSchedulable: toResourceQuantitiesDTO(frag.LargestSchedulable),
+			CPUChunks:          toChunkBucketDTOs(frag.CPUChunks),
+			MemoryChunks:       toChunkBucketDTOs(frag.MemoryChunks),
+		}
+	}
+
+	for _, class := range adv.Spec.StorageClasses {
+		classDTO := StorageClassDTO{
+			Name:        class.Name,
+			Provisioner: class.Provisioner,
+			Allocated:   class.Allocated.String(),
+		}
+		if class.Capacity != nil {
+			classDTO.Capacity = class.Capacity.String()
+		}
+		if class.Available != nil {
+			classDTO.Available = class.Available.String()
+		}
+		dto.StorageClasses = append(dto.StorageClasses, classDTO)
+	}
+
+	if caps := adv.Spec.Capabilities; caps != nil {
+		dto.Capabilities = &CapabilitiesDTO{
+			Regions:           caps.Regions,
+			Zones:             caps.Zones,
+			Architectures:     caps.Architectures,
+			OperatingSystems:  caps.OperatingSystems,
+			KubernetesVersion: caps.KubernetesVersion,
+			CNIPlugin:         caps.CNIPlugin,
+			CNIVersion:        caps.CNIVersion,
+			LiqoVersion:       caps.LiqoVersion,
+			GPUProducts:       caps.GPUProducts,
+		}
+	}
+
+	for _, gpu := range adv.Spec.GPUs {
+		gpuDTO := GPUModelDTO{
+			Product:     gpu.Product,
+			MIGStrategy: gpu.MIGStrategy,
+			NodeCount:   gpu.NodeCount,
+			Allocatable: gpu.Allocatable.String(),
+			Allocated:   gpu.Allocated.String(),
+			Available:   gpu.Available.String(),
+		}
+		if gpu.Memory != nil {
+			gpuDTO.Memory = gpu.Memory.String()
+		}
+		for _, profile := range gpu.MIGProfiles {
+			gpuDTO.MIGProfiles = append(gpuDTO.MIGProfiles, MIGProfileDTO{
+				Profile:     profile.Profile,
+				Allocatable: profile.Allocatable.String(),
+				Allocated:   profile.Allocated.String(),
+				Available:   profile.Available.String(),
+			})
+		}
+		dto.GPUs = append(dto.GPUs, gpuDTO)
+	}
+
+	if cost := adv.Spec.Cost; cost != nil {
+		dto.Cost = &CostDTO{
+			CPUCost:     cost.CPUCost,
+			MemoryCost:  cost.MemoryCost,
+			GPUCost:     cost.GPUCost,
+			StorageCost: cost.StorageCost,
+			Currency:    cost.Currency,
+		}
+		for _, pool := range cost.NodePools {
+			dto.Cost.NodePools = append(dto.Cost.NodePools, NodePoolCostDTO{
+				Name:        pool.Name,
+				CPUCost:     pool.CPUCost,
+				MemoryCost:  pool.MemoryCost,
+				GPUCost:     pool.GPUCost,
+				StorageCost: pool.StorageCost,
+			})
+		}
+	}
+
+	for _, forecast := range adv.Spec.Forecast {
+		dto.Forecast = append(dto.Forecast, ForecastDTO{
+			Horizon:   forecast.Horizon.Duration.String(),
+			Available: toResourceQuantitiesDTO(forecast.Available),
+			Method:    forecast.Method,
+			Samples:   forecast.Samples,
+		})
+	}
+
+	if maintenance := adv.Spec.Maintenance; maintenance != nil && maintenance.Enabled {
+		dto.Maintenance = &MaintenanceDTO{Reason: maintenance.Reason}
+	}
+
+	if schedule := adv.Status.Schedule; schedule != nil {
+		dto.Schedule = &ScheduleDTO{
+			Lending: schedule.Lending,
+			Window:  schedule.Window,
+		}
+		if schedule.NextTransition != nil {
+			next := schedule.NextTransition.Time
+			dto.Schedule.NextTransition = &next
+		}
+	}
+
+	if energy := adv.Spec.Energy; energy != nil {
+		dto.Energy = &EnergyDTO{
+			CarbonIntensity: energy.CarbonIntensity,
+			EnergyCost:      energy.EnergyCost,
+			Currency:        energy.Currency,
+			Source:          energy.Source,
+			ObservedAt:      energy.ObservedAt.Time,
+		}
+	}
+
 	return dto
 }
 
@@ -41,9 +195,44 @@ func toResourceQuantitiesDTO(rq rearv1alpha1.ResourceQuantities) ResourceQuantit
 		dto.Storage = rq.Storage.String()
 	}
 
+	if rq.EphemeralStorage != nil {
+		dto.EphemeralStorage = rq.EphemeralStorage.String()
+	}
+
+	if rq.Pods != nil {
+		dto.Pods = rq.Pods.String()
+	}
+
+	if len(rq.Extended) > 0 {
+		dto.Extended = make(map[string]string, len(rq.Extended))
+		for name, qty := range rq.Extended {
+			dto.Extended[name] = qty.String()
+		}
+	}
+
 	return dto
 }
 
+// toChunkBucketDTOs converts free chunk histogram buckets to DTO format
+func toChunkBucketDTOs(buckets []rearv1alpha1.FreeChunkBucket) []ChunkBucketDTO {
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	dtos := make([]ChunkBucketDTO, 0, len(buckets))
+	for _, bucket := range buckets {
+		dto := ChunkBucketDTO{
+			LowerBound: bucket.LowerBound.String(),
+			Nodes:      bucket.Nodes,
+		}
+		if bucket.UpperBound != nil {
+			dto.UpperBound = bucket.UpperBound.String()
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos
+}
+
 // fromResourceQuantitiesDTO converts DTO (string-based) to k8s ResourceQuantities
 func fromResourceQuantitiesDTO(dto ResourceQuantitiesDTO) (rearv1alpha1.ResourceQuantities, error) {
 	rq := rearv1alpha1.ResourceQuantities{}
@@ -80,6 +269,36 @@ func fromResourceQuantitiesDTO(dto ResourceQuantitiesDTO) (rearv1alpha1.Resource
 		rq.Storage = &storageQty
 	}
 
+	// Parse optional EphemeralStorage
+	if dto.EphemeralStorage != "" {
+		ephemeralQty, err := resource.ParseQuantity(dto.EphemeralStorage)
+		if err != nil {
+			return rq, err
+		}
+		rq.EphemeralStorage = &ephemeralQty
+	}
+
+	// Parse optional Pods
+	if dto.Pods != "" {
+		podsQty, err := resource.ParseQuantity(dto.Pods)
+		if err != nil {
+			return rq, err
+		}
+		rq.Pods = &podsQty
+	}
+
+	// Parse optional extended resources
+	if len(dto.Extended) > 0 {
+		rq.Extended = make(map[string]resource.Quantity, len(dto.Extended))
+		for name, value := range dto.Extended {
+			qty, err := resource.ParseQuantity(value)
+			if err != nil {
+				return rq, err
+			}
+			rq.Extended[name] = qty
+		}
+	}
+
 	return rq, nil
 }
 
@@ -137,6 +137,35 @@ func (c *HTTPCommunicator) PublishAdvertisement(ctx context.Context, adv *dto.Ad
 	return nil
 }
 
+// WithdrawAdvertisement deletes the cluster advertisement from broker via HTTP
+func (c *HTTPCommunicator) WithdrawAdvertisement(ctx context.Context, clusterID string) error {
+	logger := log.FromContext(ctx).WithName("http-communicator")
+
+	url := fmt.Sprintf("%s/api/v1/advertisements/%s", c.baseURL, clusterID)
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create DELETE request: %w", err)
+	}
+
+	resp, err := c.doWithRetry(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to withdraw advertisement: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNoContent, http.StatusNotFound:
+	default:
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("broker returned status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	logger.Info("Advertisement withdrawn successfully", "clusterID", clusterID)
+
+	return nil
+}
+
 // FetchReservations retrieves reservations for this cluster from broker
 func (c *HTTPCommunicator) FetchReservations(ctx context.Context, clusterID string, role dto.Role) ([]*dto.ReservationDTO, error) {
 	logger := log.FromContext(ctx).WithName("http-communicator")
@@ -197,6 +197,7 @@ func (p *ReservationPoller) createProviderInstruction(ctx context.Context, rsv *
 			RequesterClusterID: rsv.RequesterID,
 			RequestedCPU:       rsv.RequestedResources.CPU,
 			RequestedMemory:    rsv.RequestedResources.Memory,
+			RequestedStorage:   rsv.RequestedResources.Storage,
 			Message: fmt.Sprintf("Hold %s CPU / %s Memory for requester %s",
 				rsv.RequestedResources.CPU,
 				rsv.RequestedResources.Memory,
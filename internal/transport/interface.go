@@ -12,6 +12,10 @@ type BrokerCommunicator interface {
 	// PublishAdvertisement publishes cluster resource advertisement to broker
 	PublishAdvertisement(ctx context.Context, adv *dto.AdvertisementDTO) error
 
+	// WithdrawAdvertisement removes the advertisement of the cluster from the broker,
+	// so that it stops receiving reservations. Withdrawing an unknown cluster is not an error.
+	WithdrawAdvertisement(ctx context.Context, clusterID string) error
+
 	// FetchReservations retrieves reservations for this cluster by role
 	FetchReservations(ctx context.Context, clusterID string, role dto.Role) ([]*dto.ReservationDTO, error)
 